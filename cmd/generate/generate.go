@@ -2,9 +2,11 @@ package generate
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +27,9 @@ var (
 	duration      int
 	concurrency   int
 	interval      int
+	timeFormats   []string
+	timeSeries    bool
+	metaField     string
 )
 
 // NewGenerateCmd creates a generate command
@@ -44,6 +49,10 @@ func NewGenerateCmd() *cobra.Command {
 	cmd.Flags().IntVar(&duration, "duration", 0, "How long to run in minutes (0 for infinite)")
 	cmd.Flags().IntVar(&concurrency, "concurrency", 5, "Number of concurrent insertion operations")
 	cmd.Flags().IntVar(&interval, "interval", 60, "Interval between batch generations in seconds")
+	cmd.Flags().StringSliceVar(&timeFormats, "timestamp-format", []string{models.TimestampDate},
+		fmt.Sprintf("Timestamp formats to write, each into its own sibling collection (%s)", strings.Join(models.TimestampFormats, ", ")))
+	cmd.Flags().BoolVar(&timeSeries, "timeseries", false, "Create the events collection as a time-series collection")
+	cmd.Flags().StringVar(&metaField, "meta-field", "sourceSystem", "Time-series metaField (sourceSystem or eventType)")
 
 	return cmd
 }
@@ -71,12 +80,23 @@ func generateCmd(cmd *cobra.Command, args []string) {
 		}
 	}()
 
-	// Get events collection
-	eventsCollection := database.GetEventsCollection(client, config.Database)
+	// Get events collection for every requested timestamp format
+	collections := make(map[string]*mongo.Collection)
+	for _, format := range timeFormats {
+		if !isTimestampFormat(format) {
+			log.Fatalf("Unknown timestamp format: %s", format)
+		}
+		collections[format] = client.Database(config.Database).Collection(database.EventsCollectionNameFor(format))
 
-	_, err = database.CreateEventIndexes(ctx, eventsCollection)
-	if err != nil {
-		log.Fatalf("Failed to create indexes: %v", err)
+		// Only BSON Date timestamps can serve as a time-series timeField
+		if timeSeries && format == models.TimestampDate {
+			createTimeSeriesEvents(ctx, client.Database(config.Database), collections[format].Name())
+		}
+
+		_, err = database.CreateEventIndexes(ctx, collections[format])
+		if err != nil {
+			log.Fatalf("Failed to create indexes: %v", err)
+		}
 	}
 
 	// Log startup information
@@ -85,7 +105,7 @@ func generateCmd(cmd *cobra.Command, args []string) {
 	fmt.Println("Press Ctrl+C to stop")
 
 	// Generate an event immediately on startup
-	generateAndInsertEvents(ctx, eventsCollection, concurrency)
+	generateAndInsertEvents(ctx, collections, concurrency)
 
 	// Calculate end time if duration is set
 	var endTime time.Time
@@ -110,13 +130,13 @@ func generateCmd(cmd *cobra.Command, args []string) {
 			}
 
 			// Generate and insert events concurrently
-			generateAndInsertEvents(ctx, eventsCollection, concurrency)
+			generateAndInsertEvents(ctx, collections, concurrency)
 		}
 	}
 }
 
-// Generate and insert events concurrently
-func generateAndInsertEvents(ctx context.Context, collection *mongo.Collection, concurrency int) {
+// Generate and insert events concurrently, once per timestamp format collection
+func generateAndInsertEvents(ctx context.Context, collections map[string]*mongo.Collection, concurrency int) {
 	eventCount := rand.Intn(10000) // todo: extract to config
 	if eventCount == 0 {
 		fmt.Println("No events generated in this interval")
@@ -137,9 +157,16 @@ func generateAndInsertEvents(ctx context.Context, collection *mongo.Collection,
 		// Insert event concurrently
 		go func(evt models.Event) {
 			defer wg.Done()
-			_, err := collection.InsertOne(ctx, evt)
-			if err != nil {
-				log.Printf("Failed to insert event: %v", err)
+			for format, collection := range collections {
+				doc, err := utils.ConvertTimestamp(evt, format)
+				if err != nil {
+					log.Printf("Failed to convert event: %v", err)
+					continue
+				}
+				_, err = collection.InsertOne(ctx, doc)
+				if err != nil {
+					log.Printf("Failed to insert event: %v", err)
+				}
 			}
 		}(e)
 
@@ -153,3 +180,31 @@ func generateAndInsertEvents(ctx context.Context, collection *mongo.Collection,
 	wg.Wait()
 	fmt.Println("All events successfully inserted")
 }
+
+// Check whether the format is a known timestamp format
+func isTimestampFormat(format string) bool {
+	for _, f := range models.TimestampFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Create the events collection as a time-series collection unless it already exists
+func createTimeSeriesEvents(ctx context.Context, db *mongo.Database, name string) {
+	if metaField != "sourceSystem" && metaField != "eventType" {
+		log.Fatalf("Unknown time-series meta field: %s", metaField)
+	}
+
+	err := database.CreateTimeSeriesCollection(ctx, db, name, metaField)
+	var se mongo.ServerError
+	if errors.As(err, &se) && se.HasErrorCode(48) {
+		fmt.Printf("Collection %s already exists, keeping its current layout\n", name)
+		return
+	}
+	if err != nil {
+		log.Fatalf("Failed to create time-series collection: %v", err)
+	}
+	fmt.Printf("Created time-series collection %s with metaField %s\n", name, metaField)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"mongo-bench/internal/database"
 	"mongo-bench/internal/utils"
@@ -19,6 +20,8 @@ var (
 	mongoPassword string
 	mongoDatabase string
 	testsName     []string
+	maxTime       time.Duration
+	timeout       time.Duration
 )
 
 // NewRunCmd creates a run benchmark command
@@ -36,6 +39,8 @@ func NewRunCmd() *cobra.Command {
 	cmd.Flags().StringVar(&mongoPassword, "password", "password", "MongoDB password")
 	cmd.Flags().StringVar(&mongoDatabase, "database", "eventstore", "MongoDB database name")
 	cmd.Flags().StringSliceVar(&testsName, "test", []string{}, "Specify test name to run")
+	cmd.Flags().DurationVar(&maxTime, "max-time", 0, "Server-side time limit (maxTimeMS) for every query, 0 for none")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Client-side deadline for every test, 0 for none")
 
 	return cmd
 }
@@ -67,12 +72,6 @@ func runBenchmarkCmd(cmd *cobra.Command, args []string) {
 	// Get collection
 	eventsCollection := database.GetEventsCollection(client, config.Database)
 
-	// Create query context
-	queryContext := &utils.QueryContext{
-		Ctx:        ctx,
-		Collection: eventsCollection,
-	}
-
 	// Get all test functions
 	testPairs := utils.GetQueryTestPairs()
 
@@ -108,13 +107,30 @@ func runBenchmarkCmd(cmd *cobra.Command, args []string) {
 		fmt.Printf("\nRunning test: %s\n", pair.Name)
 		fmt.Println(strings.Repeat("-", 40))
 
+		// Create query context, bounded by the deadline when one is given
+		testCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout > 0 {
+			testCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		queryContext := &utils.QueryContext{
+			Ctx:        testCtx,
+			Collection: eventsCollection,
+			MaxTime:    maxTime,
+		}
+
 		// Execute test and analyze performance
 		result, err := utils.ProfileFunc(pair.Name, func() error {
 			return pair.TestFunc(queryContext)
 		})
+		cancel()
 		if err != nil {
-			log.Printf("Test failed: %v", err)
-			continue
+			// Timed-out queries are reported in the summary instead of being dropped
+			if !utils.IsTimedOut(err) {
+				log.Printf("Test failed: %v", err)
+				continue
+			}
+			log.Printf("Test timed out: %v", err)
+			result.TimedOut = true
 		}
 
 		// Save result
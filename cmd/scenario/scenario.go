@@ -0,0 +1,136 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"mongo-bench/internal/database"
+	"mongo-bench/internal/scenarios"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// Command parameters
+	mongoURI      string
+	mongoUsername string
+	mongoPassword string
+	mongoDatabase string
+	scenarioNames []string
+	docs          int
+	collections   int
+	keep          bool
+)
+
+// NewScenarioCmd creates a run scenario command
+func NewScenarioCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scenario",
+		Short: "Execute MongoDB anti-pattern scenarios",
+		Long:  "Execute anti-pattern scenarios that set up their own collections and compare the measurements of each variant",
+		Run:   runScenarioCmd,
+	}
+
+	// Add parameters
+	cmd.Flags().StringVar(&mongoURI, "uri", "mongodb://localhost:27017", "MongoDB connection URI")
+	cmd.Flags().StringVar(&mongoUsername, "username", "admin", "MongoDB username")
+	cmd.Flags().StringVar(&mongoPassword, "password", "password", "MongoDB password")
+	cmd.Flags().StringVar(&mongoDatabase, "database", "eventstore", "MongoDB database name")
+	cmd.Flags().StringSliceVar(&scenarioNames, "scenario", []string{}, "Specify scenario name to run")
+	cmd.Flags().IntVar(&docs, "docs", 10000, "Number of events each scenario generates")
+	cmd.Flags().IntVar(&collections, "collections", 100, "Number of collections CollectionExplosion spreads events across")
+	cmd.Flags().BoolVar(&keep, "keep", false, "Keep scenario collections after the run")
+
+	return cmd
+}
+
+// Execute scenario command
+func runScenarioCmd(cmd *cobra.Command, args []string) {
+	// Create context
+	ctx := context.Background()
+
+	// Configure MongoDB connection
+	config := database.MongoConfig{
+		URI:      mongoURI,
+		Username: mongoUsername,
+		Password: mongoPassword,
+		Database: mongoDatabase,
+	}
+
+	// Connect to MongoDB
+	client, err := database.ConnectMongoDB(ctx, config)
+	if err != nil {
+		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	}
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatalf("Failed to disconnect from MongoDB: %v", err)
+		}
+	}()
+
+	// Create scenario context
+	scenarioContext := &scenarios.ScenarioContext{
+		Ctx:         ctx,
+		Config:      config,
+		Client:      client,
+		Database:    client.Database(config.Database),
+		Docs:        docs,
+		Collections: collections,
+	}
+
+	// Get all scenarios
+	all := scenarios.GetScenarios()
+
+	// If specific scenarios are specified, only run these scenarios
+	if len(scenarioNames) > 0 {
+		nameMap := make(map[string]bool)
+		for _, name := range scenarioNames {
+			nameMap[name] = true
+		}
+
+		var selected []scenarios.Scenario
+		for _, s := range all {
+			if nameMap[s.Name] {
+				selected = append(selected, s)
+			}
+		}
+
+		if len(selected) == 0 {
+			log.Fatalf("No matching scenarios found for the specified scenario names")
+		}
+		all = selected
+	}
+
+	fmt.Println("\nRunning anti-pattern scenarios...")
+	fmt.Println(strings.Repeat("=", 50))
+
+	// Record all variant results
+	var results []scenarios.VariantResult
+
+	// Execute scenarios one by one
+	for _, s := range all {
+		fmt.Printf("\nRunning scenario: %s\n", s.Name)
+		fmt.Println(strings.Repeat("-", 40))
+
+		variants, err := s.RunFunc(scenarioContext)
+		results = append(results, variants...)
+		if err != nil {
+			log.Printf("Scenario failed: %v", err)
+		}
+
+		if !keep {
+			scenarioContext.Cleanup()
+		}
+		fmt.Println(strings.Repeat("-", 40))
+	}
+
+	// Print all variant results summary
+	fmt.Println("\nScenario Results Summary:")
+	fmt.Println(strings.Repeat("=", 50))
+	for _, result := range results {
+		fmt.Println(result.String())
+		fmt.Println(strings.Repeat("-", 30))
+	}
+}
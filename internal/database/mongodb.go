@@ -5,6 +5,8 @@ import (
 	"log"
 	"time"
 
+	"mongo-bench/internal/models"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -37,8 +39,9 @@ type MongoConfig struct {
 	Database string
 }
 
-// ConnectMongoDB establishes a connection to MongoDB
-func ConnectMongoDB(ctx context.Context, config MongoConfig) (*mongo.Client, error) {
+// ConnectMongoDB establishes a connection to MongoDB.
+// Extra client options are applied on top of the configuration.
+func ConnectMongoDB(ctx context.Context, config MongoConfig, opts ...*options.ClientOptions) (*mongo.Client, error) {
 	// Create client options
 	clientOptions := options.Client().ApplyURI(config.URI)
 
@@ -51,7 +54,7 @@ func ConnectMongoDB(ctx context.Context, config MongoConfig) (*mongo.Client, err
 	}
 
 	// Connect to MongoDB
-	client, err := mongo.Connect(ctx, clientOptions)
+	client, err := mongo.Connect(ctx, append([]*options.ClientOptions{clientOptions}, opts...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -70,10 +73,27 @@ func GetEventsCollection(client *mongo.Client, database string) *mongo.Collectio
 	return client.Database(database).Collection(EventsCollectionName)
 }
 
-// CreateEventIndexes creates indexes for the events collection
-func CreateEventIndexes(ctx context.Context, collection *mongo.Collection) ([]string, error) {
-	// Create indexes for better query performance
-	indexes := []mongo.IndexModel{
+// EventsCollectionNameFor returns the events collection holding timestamps in the given format
+func EventsCollectionNameFor(format string) string {
+	if format == models.TimestampDate {
+		return EventsCollectionName
+	}
+	return EventsCollectionName + "_" + format + "_ts"
+}
+
+// CreateTimeSeriesCollection creates a time-series collection keyed on the event timestamp
+func CreateTimeSeriesCollection(ctx context.Context, db *mongo.Database, name, metaField string) error {
+	tsOptions := options.TimeSeries().SetTimeField("timestamp")
+	if metaField != "" {
+		tsOptions.SetMetaField(metaField)
+	}
+
+	return db.CreateCollection(ctx, name, options.CreateCollection().SetTimeSeriesOptions(tsOptions))
+}
+
+// EventIndexes returns the index models kept on the events collection
+func EventIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
 		{
 			Keys: bson.D{
 				{Key: "timestamp", Value: -1},
@@ -90,6 +110,12 @@ func CreateEventIndexes(ctx context.Context, collection *mongo.Collection) ([]st
 			Options: options.Index().SetName(Timestamp_SeverityLevel_Index),
 		},
 	}
+}
+
+// CreateEventIndexes creates indexes for the events collection
+func CreateEventIndexes(ctx context.Context, collection *mongo.Collection) ([]string, error) {
+	// Create indexes for better query performance
+	indexes := EventIndexes()
 
 	// Create indexes with a timeout
 	indexCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -32,6 +32,13 @@ type Event struct {
 	Metadata           map[string]interface{} `bson:"metadata"`
 }
 
+// Timestamp formats events can be stored with
+const (
+	TimestampDate   = "date"
+	TimestampString = "string"
+	TimestampInt64  = "int64"
+)
+
 // Constants and sample data
 var (
 	EventTypes = []string{
@@ -107,6 +114,12 @@ var (
 		"Operations Team",
 	}
 
+	TimestampFormats = []string{
+		TimestampDate,
+		TimestampString,
+		TimestampInt64,
+	}
+
 	SeverityLevels = []SeverityLevel{
 		{Level: 0, Label: "Information", Color: "blue"},
 		{Level: 1, Label: "Low", Color: "green"},
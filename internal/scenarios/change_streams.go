@@ -0,0 +1,245 @@
+package scenarios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"mongo-bench/internal/models"
+	"mongo-bench/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Number of events the writer inserts while each consumer runs
+	feedEvents = 1000
+	// Pause between two writer inserts
+	feedWriteGap = 5 * time.Millisecond
+	// Number of events a FindRecentEvents-style poll returns
+	feedPollLimit = 10
+)
+
+// Poll intervals compared against the change stream
+var feedPollIntervals = []time.Duration{
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// deliveryLog records which events a consumer saw and how late
+type deliveryLog struct {
+	mu         sync.Mutex
+	seen       map[string]int
+	latencies  []time.Duration
+	roundTrips int
+}
+
+// record notes one delivery of an event, measuring latency on first sight only
+func (d *deliveryLog) record(e models.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id, _ := e.Metadata["eventId"].(string)
+	d.seen[id]++
+	if d.seen[id] == 1 {
+		d.latencies = append(d.latencies, time.Since(e.Timestamp))
+	}
+}
+
+// ChangeStreams compares polling for recent events at several intervals with a change stream consumer
+func ChangeStreams(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Polling for new events instead of watching a change stream")
+
+	variants := []string{"Change stream"}
+	for _, interval := range feedPollIntervals {
+		variants = append(variants, fmt.Sprintf("Polling every %v", interval))
+	}
+
+	ok, err := isReplicaSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return unsupportedResults("ChangeStreams", variants, "change streams require a replica set"), nil
+	}
+
+	consumers := []func(context.Context, *mongo.Collection, *deliveryLog, chan<- struct{}) error{watchEvents}
+	for _, interval := range feedPollIntervals {
+		consumers = append(consumers, pollEvents(interval))
+	}
+
+	var results []VariantResult
+	for i, consume := range consumers {
+		fmt.Printf("Measuring consumer: %s\n", variants[i])
+
+		coll, err := ctx.Collection("change_feed")
+		if err != nil {
+			return results, err
+		}
+		if _, err := coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "timestamp", Value: -1}},
+		}); err != nil {
+			return results, err
+		}
+
+		before, err := readOpcounters(ctx)
+		if err != nil {
+			return results, err
+		}
+
+		// Start the consumer and wait until it is ready before writing
+		consumerCtx, cancel := context.WithCancel(ctx.Ctx)
+		deliveries := &deliveryLog{seen: make(map[string]int)}
+		ready := make(chan struct{})
+		consumerErr := make(chan error, 1)
+		go func() {
+			consumerErr <- consume(consumerCtx, coll, deliveries, ready)
+		}()
+		<-ready
+
+		ids, writeErr := writeSteadily(ctx.Ctx, coll)
+
+		// Give the slowest consumer one more round to catch up
+		time.Sleep(feedPollIntervals[len(feedPollIntervals)-1] + 200*time.Millisecond)
+		cancel()
+		if err := <-consumerErr; err != nil && !errors.Is(err, context.Canceled) {
+			return results, err
+		}
+		if writeErr != nil {
+			return results, writeErr
+		}
+
+		after, err := readOpcounters(ctx)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, deliveryResult(variants[i], ids, deliveries, before, after))
+	}
+
+	return results, nil
+}
+
+// writeSteadily inserts events one at a time at a fixed pace and returns their event IDs
+func writeSteadily(ctx context.Context, coll *mongo.Collection) ([]string, error) {
+	ids := make([]string, 0, feedEvents)
+	for i := 0; i < feedEvents; i++ {
+		e := utils.GenerateRandomEvent()
+		if _, err := coll.InsertOne(ctx, e); err != nil {
+			return ids, err
+		}
+		ids = append(ids, e.Metadata["eventId"].(string))
+		time.Sleep(feedWriteGap)
+	}
+	return ids, nil
+}
+
+// watchEvents consumes inserts through a change stream until the context is cancelled
+func watchEvents(ctx context.Context, coll *mongo.Collection, deliveries *deliveryLog, ready chan<- struct{}) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"operationType": "insert"}}},
+	}
+
+	stream, err := coll.Watch(ctx, pipeline)
+	close(ready)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.Background())
+
+	for stream.Next(ctx) {
+		var change struct {
+			FullDocument models.Event `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&change); err != nil {
+			return err
+		}
+		deliveries.record(change.FullDocument)
+	}
+	// A stream that dies on its own must fail the scenario instead of reporting missed events
+	if ctx.Err() == nil {
+		return stream.Err()
+	}
+	return ctx.Err()
+}
+
+// pollEvents returns a consumer that repeatedly fetches the most recent events at the given interval
+func pollEvents(interval time.Duration) func(context.Context, *mongo.Collection, *deliveryLog, chan<- struct{}) error {
+	return func(ctx context.Context, coll *mongo.Collection, deliveries *deliveryLog, ready chan<- struct{}) error {
+		close(ready)
+
+		opts := options.Find().
+			SetSort(bson.M{"timestamp": -1}).
+			SetLimit(feedPollLimit)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+				cursor, err := coll.Find(ctx, bson.M{}, opts)
+				if err != nil {
+					return err
+				}
+
+				var events []models.Event
+				err = cursor.All(ctx, &events)
+				cursor.Close(ctx)
+				if err != nil {
+					return err
+				}
+
+				deliveries.mu.Lock()
+				deliveries.roundTrips++
+				deliveries.mu.Unlock()
+				for _, e := range events {
+					deliveries.record(e)
+				}
+			}
+		}
+	}
+}
+
+// deliveryResult summarizes what a consumer delivered compared with what was written
+func deliveryResult(variant string, ids []string, deliveries *deliveryLog, before, after map[string]int64) VariantResult {
+	missed, duplicates := 0, 0
+	for _, id := range ids {
+		switch n := deliveries.seen[id]; {
+		case n == 0:
+			missed++
+		case n > 1:
+			duplicates += n - 1
+		}
+	}
+
+	avg, slowest := latencySummary(deliveries.latencies)
+
+	result := VariantResult{
+		Scenario: "ChangeStreams",
+		Variant:  variant,
+		Outcome:  OutcomeOK,
+		Metrics: []Metric{
+			{"Events written", len(ids)},
+			{"Events delivered", len(ids) - missed},
+			{"Missed", missed},
+			{"Duplicates", duplicates},
+			{"Avg delivery latency", avg},
+			{"Max delivery latency", slowest},
+			{"Server query ops", after["query"] - before["query"]},
+			{"Server getmore ops", after["getmore"] - before["getmore"]},
+			{"Server command ops", after["command"] - before["command"]},
+		},
+	}
+	if deliveries.roundTrips > 0 {
+		result.Metrics = append(result.Metrics, Metric{"Polls", deliveries.roundTrips})
+	}
+	return result
+}
@@ -0,0 +1,247 @@
+package scenarios
+
+import (
+	"fmt"
+	"time"
+
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CollectionExplosion spreads the same events across one collection per tenant and
+// compares that with a single collection holding a tenantId discriminator
+func CollectionExplosion(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: One collection per tenant or source")
+
+	tenants := ctx.Collections
+	if tenants < 1 {
+		tenants = 1
+	}
+
+	// Events are assigned to tenants round-robin, so every tenant gets a similar share
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+	byTenant := make([][]models.Event, tenants)
+	for i, e := range events {
+		byTenant[i%tenants] = append(byTenant[i%tenants], e)
+	}
+
+	lastHour := bson.M{"timestamp": bson.M{"$gte": time.Now().Add(-time.Hour)}, "severity.level": bson.M{"$gte": 3}}
+	timestampIndex := mongo.IndexModel{Keys: bson.D{{Key: "timestamp", Value: -1}}}
+
+	var results []VariantResult
+
+	// One collection per tenant, each with its own timestamp index
+	name := fmt.Sprintf("Collection per tenant, %d collections", tenants)
+	fmt.Printf("Measuring layout: %s\n", name)
+
+	catalogBefore, err := readDBStats(ctx)
+	if err != nil {
+		return results, err
+	}
+
+	begin := time.Now()
+	perTenant := make([]*mongo.Collection, 0, tenants)
+	for t := 0; t < tenants; t++ {
+		coll, err := ctx.Collection(fmt.Sprintf("tenant_%05d", t))
+		if err != nil {
+			return results, err
+		}
+		if _, err := coll.Indexes().CreateOne(ctx.Ctx, timestampIndex); err != nil {
+			return results, err
+		}
+		perTenant = append(perTenant, coll)
+	}
+	setup := time.Since(begin)
+
+	begin = time.Now()
+	for t, coll := range perTenant {
+		if len(byTenant[t]) == 0 {
+			continue
+		}
+		if err := seedEvents(ctx.Ctx, coll, byTenant[t]); err != nil {
+			return results, err
+		}
+	}
+	insertTime := time.Since(begin)
+
+	// Applications typically ensure their indexes on every collection they use at startup
+	begin = time.Now()
+	if _, err := ctx.Database.ListCollectionNames(ctx.Ctx, bson.M{}); err != nil {
+		return results, err
+	}
+	for _, coll := range perTenant {
+		if _, err := coll.Indexes().CreateOne(ctx.Ctx, timestampIndex); err != nil {
+			return results, err
+		}
+	}
+	startup := time.Since(begin)
+
+	begin = time.Now()
+	if _, err := perTenant[0].CountDocuments(ctx.Ctx, lastHour); err != nil {
+		return results, err
+	}
+	singleTenantQuery := time.Since(begin)
+
+	begin = time.Now()
+	var crossTenantCount int64
+	for _, coll := range perTenant {
+		n, err := coll.CountDocuments(ctx.Ctx, lastHour)
+		if err != nil {
+			return results, err
+		}
+		crossTenantCount += n
+	}
+	crossTenantQuery := time.Since(begin)
+
+	catalogAfter, err := readDBStats(ctx)
+	if err != nil {
+		return results, err
+	}
+
+	var storage, indexSize int64
+	for _, coll := range perTenant {
+		stats, err := getCollectionStats(ctx.Ctx, coll)
+		if err != nil {
+			return results, err
+		}
+		storage += stats.StorageSize
+		indexSize += stats.TotalIndexSize
+	}
+
+	results = append(results, explosionResult(name, len(events), catalogBefore, catalogAfter,
+		setup, insertTime, startup, singleTenantQuery, crossTenantQuery, crossTenantCount, storage, indexSize))
+
+	// A single collection with the tenant as a discriminator field
+	name = "Single collection with tenantId"
+	fmt.Printf("Measuring layout: %s\n", name)
+
+	catalogBefore, err = readDBStats(ctx)
+	if err != nil {
+		return results, err
+	}
+
+	begin = time.Now()
+	shared, err := ctx.Collection("tenants_shared")
+	if err != nil {
+		return results, err
+	}
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "timestamp", Value: -1}}},
+		timestampIndex,
+	}
+	if _, err := shared.Indexes().CreateMany(ctx.Ctx, indexModels); err != nil {
+		return results, err
+	}
+	setup = time.Since(begin)
+
+	docs := make([]interface{}, 0, len(events))
+	for i, e := range events {
+		doc, err := eventDoc(e)
+		if err != nil {
+			return results, err
+		}
+		docs = append(docs, append(doc, bson.E{Key: "tenantId", Value: i % tenants}))
+	}
+	begin = time.Now()
+	if err := insertBatches(ctx.Ctx, shared, docs); err != nil {
+		return results, err
+	}
+	insertTime = time.Since(begin)
+
+	begin = time.Now()
+	if _, err := ctx.Database.ListCollectionNames(ctx.Ctx, bson.M{}); err != nil {
+		return results, err
+	}
+	if _, err := shared.Indexes().CreateMany(ctx.Ctx, indexModels); err != nil {
+		return results, err
+	}
+	startup = time.Since(begin)
+
+	tenantFilter := bson.M{"tenantId": 0}
+	for k, v := range lastHour {
+		tenantFilter[k] = v
+	}
+	begin = time.Now()
+	if _, err := shared.CountDocuments(ctx.Ctx, tenantFilter); err != nil {
+		return results, err
+	}
+	singleTenantQuery = time.Since(begin)
+
+	begin = time.Now()
+	crossTenantCount, err = shared.CountDocuments(ctx.Ctx, lastHour)
+	if err != nil {
+		return results, err
+	}
+	crossTenantQuery = time.Since(begin)
+
+	catalogAfter, err = readDBStats(ctx)
+	if err != nil {
+		return results, err
+	}
+	stats, err := getCollectionStats(ctx.Ctx, shared)
+	if err != nil {
+		return results, err
+	}
+
+	results = append(results, explosionResult(name, len(events), catalogBefore, catalogAfter,
+		setup, insertTime, startup, singleTenantQuery, crossTenantQuery, crossTenantCount, stats.StorageSize, stats.TotalIndexSize))
+
+	return results, nil
+}
+
+// dbStats holds the catalog counters of the scenario database
+type dbStats struct {
+	Collections int64
+	Indexes     int64
+	FsUsedSize  int64
+}
+
+// readDBStats runs dbStats on the scenario database
+func readDBStats(ctx *ScenarioContext) (dbStats, error) {
+	var raw bson.M
+	if err := ctx.Database.RunCommand(ctx.Ctx, bson.D{{Key: "dbStats", Value: 1}}).Decode(&raw); err != nil {
+		return dbStats{}, err
+	}
+	return dbStats{
+		Collections: toInt64(raw["collections"]),
+		Indexes:     toInt64(raw["indexes"]),
+		FsUsedSize:  toInt64(raw["fsUsedSize"]),
+	}, nil
+}
+
+// explosionResult builds the result of one collection layout from the dbStats taken around it
+func explosionResult(name string, events int, before, after dbStats,
+	setup, insertTime, startup, singleTenantQuery, crossTenantQuery time.Duration,
+	crossTenantCount, storage, indexSize int64) VariantResult {
+	collections := after.Collections - before.Collections
+	indexes := after.Indexes - before.Indexes
+
+	// Every collection and index is its own WiredTiger file with a fixed minimum footprint
+	fsGrowth := after.FsUsedSize - before.FsUsedSize
+	perFile := int64(0)
+	if collections+indexes > 0 {
+		perFile = fsGrowth / (collections + indexes)
+	}
+
+	return VariantResult{
+		Scenario: "CollectionExplosion",
+		Variant:  name,
+		Outcome:  OutcomeOK,
+		Metrics: []Metric{
+			{"Collections added to catalog", collections},
+			{"Indexes added to catalog", indexes},
+			{"Filesystem growth", formatMB(fsGrowth)},
+			{"Filesystem growth per data file", formatMB(perFile)},
+			{"Collection and index creation", setup},
+			{"Insert throughput (events/s)", float64(events) / insertTime.Seconds()},
+			{"Startup catalog and index check", startup},
+			{"Single-tenant query latency", singleTenantQuery},
+			{"Cross-tenant query latency", crossTenantQuery},
+			{"Cross-tenant matches", crossTenantCount},
+			{"Storage size", formatMB(storage)},
+			{"Index size", formatMB(indexSize)},
+		},
+	}
+}
@@ -0,0 +1,123 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Number of workers upserting the same events in the same order
+	upsertWorkers = 4
+	// How often a duplicate-key error is retried before giving up
+	upsertMaxRetries = 3
+)
+
+// ConcurrentUpserts races upserts on metadata.eventId without and with a unique index
+func ConcurrentUpserts(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Deduplicating with upserts but no unique index")
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+
+	variants := []struct {
+		name   string
+		unique bool
+	}{
+		{"Upsert without unique index", false},
+		{"Upsert with unique index and duplicate-key retry", true},
+	}
+
+	var results []VariantResult
+	for _, v := range variants {
+		fmt.Printf("Measuring upserts: %s\n", v.name)
+
+		coll, err := ctx.Collection("concurrent_upserts")
+		if err != nil {
+			return results, err
+		}
+		// Both variants index the event ID; only the second makes it unique
+		if _, err := coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+			Keys:    bson.D{{Key: "metadata.eventId", Value: 1}},
+			Options: options.Index().SetUnique(v.unique),
+		}); err != nil {
+			return results, err
+		}
+
+		var (
+			retries  int64
+			failed   int64
+			firstErr error
+			errOnce  sync.Once
+			wg       sync.WaitGroup
+		)
+
+		// Every worker walks the same events in the same order, so they collide constantly
+		begin := time.Now()
+		for w := 0; w < upsertWorkers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, e := range events {
+					n, err := upsertEvent(ctx.Ctx, coll, e, v.unique)
+					atomic.AddInt64(&retries, int64(n))
+					if err != nil {
+						atomic.AddInt64(&failed, 1)
+						errOnce.Do(func() { firstErr = err })
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		elapsed := time.Since(begin)
+
+		stored, err := coll.CountDocuments(ctx.Ctx, bson.M{})
+		if err != nil {
+			return results, err
+		}
+
+		upserts := len(events) * upsertWorkers
+		result := VariantResult{
+			Scenario: "ConcurrentUpserts",
+			Variant:  v.name,
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Upserts", upserts},
+				{"Throughput (upserts/s)", float64(upserts) / elapsed.Seconds()},
+				{"Distinct events", len(events)},
+				{"Documents stored", stored},
+				{"Duplicates", stored - int64(len(events))},
+				{"Duplicate-key retries", retries},
+				{"Failed upserts", failed},
+			},
+		}
+		if firstErr != nil {
+			result.Metrics = append(result.Metrics, Metric{"First error", firstErr.Error()})
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// upsertEvent inserts the event unless one with the same event ID exists,
+// retrying duplicate-key errors when asked to, and returns the number of retries
+func upsertEvent(ctx context.Context, coll *mongo.Collection, e models.Event, retry bool) (int, error) {
+	filter := bson.M{"metadata.eventId": e.Metadata["eventId"]}
+	update := bson.M{"$setOnInsert": e}
+	opts := options.Update().SetUpsert(true)
+
+	for attempt := 0; ; attempt++ {
+		_, err := coll.UpdateOne(ctx, filter, update, opts)
+		if err == nil || !retry || !mongo.IsDuplicateKeyError(err) || attempt == upsertMaxRetries {
+			return attempt, err
+		}
+	}
+}
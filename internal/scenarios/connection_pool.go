@@ -0,0 +1,191 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"mongo-bench/internal/database"
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Number of simple queries each variant runs
+	poolQueries = 500
+	// Number of concurrent callers issuing the queries
+	poolWorkers = 8
+	// Number of events the queried collection holds
+	poolDocs = 1000
+)
+
+// poolCounter counts connection pool events across every client it is attached to
+type poolCounter struct {
+	created int64
+	ready   int64
+	closed  int64
+	peak    int64
+}
+
+// monitor returns a pool monitor feeding the counter
+func (c *poolCounter) monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			switch e.Type {
+			case event.ConnectionCreated:
+				created := atomic.AddInt64(&c.created, 1)
+				c.raisePeak(created - atomic.LoadInt64(&c.closed))
+			case event.ConnectionReady:
+				atomic.AddInt64(&c.ready, 1)
+			case event.ConnectionClosed:
+				atomic.AddInt64(&c.closed, 1)
+			}
+		},
+	}
+}
+
+// raisePeak records open as the peak unless a concurrent event already recorded a higher one
+func (c *poolCounter) raisePeak(open int64) {
+	for {
+		peak := atomic.LoadInt64(&c.peak)
+		if open <= peak || atomic.CompareAndSwapInt64(&c.peak, peak, open) {
+			return
+		}
+	}
+}
+
+// ConnectionPool compares a new client per query with a shared client using the default and a tuned pool
+func ConnectionPool(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Connecting a new client for every operation")
+
+	coll, err := ctx.Collection("connection_pool")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, coll, generateEvents(poolDocs, 24*time.Hour)); err != nil {
+		return nil, err
+	}
+
+	variants := []struct {
+		name string
+		// shared is nil when every query connects its own client
+		shared *options.ClientOptions
+	}{
+		{"New client per query", nil},
+		{"Shared client, default pool", options.Client()},
+		{"Shared client, tuned pool", options.Client().
+			SetMinPoolSize(poolWorkers).
+			SetMaxPoolSize(poolWorkers)},
+	}
+
+	var results []VariantResult
+	for _, v := range variants {
+		fmt.Printf("Measuring connections: %s\n", v.name)
+
+		counter := &poolCounter{}
+		monitored := options.Client().SetPoolMonitor(counter.monitor())
+
+		// query runs one simple lookup the way a request handler would
+		var query func(ctx context.Context) error
+		var shared *mongo.Client
+		if v.shared == nil {
+			query = func(qctx context.Context) error {
+				client, err := database.ConnectMongoDB(qctx, ctx.Config, monitored)
+				if err != nil {
+					return err
+				}
+				defer client.Disconnect(qctx)
+				return findOneBySource(qctx, client.Database(ctx.Database.Name()).Collection(coll.Name()))
+			}
+		} else {
+			shared, err = database.ConnectMongoDB(ctx.Ctx, ctx.Config, monitored, v.shared)
+			if err != nil {
+				return results, err
+			}
+			sharedColl := shared.Database(ctx.Database.Name()).Collection(coll.Name())
+			query = func(qctx context.Context) error {
+				return findOneBySource(qctx, sharedColl)
+			}
+		}
+
+		var (
+			mu        sync.Mutex
+			latencies []time.Duration
+			firstErr  error
+			wg        sync.WaitGroup
+		)
+		queries := make(chan struct{})
+		begin := time.Now()
+		for w := 0; w < poolWorkers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range queries {
+					start := time.Now()
+					err := query(ctx.Ctx)
+					elapsed := time.Since(start)
+
+					mu.Lock()
+					latencies = append(latencies, elapsed)
+					if err != nil && firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+		for i := 0; i < poolQueries; i++ {
+			queries <- struct{}{}
+		}
+		close(queries)
+		wg.Wait()
+		elapsed := time.Since(begin)
+
+		// Connections still open before the shared client is shut down
+		openAtEnd := atomic.LoadInt64(&counter.created) - atomic.LoadInt64(&counter.closed)
+		if shared != nil {
+			if err := shared.Disconnect(ctx.Ctx); err != nil {
+				return results, err
+			}
+		}
+		if firstErr != nil {
+			return results, firstErr
+		}
+
+		avg, slowest := latencySummary(latencies)
+		results = append(results, VariantResult{
+			Scenario: "ConnectionPool",
+			Variant:  v.name,
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Queries", len(latencies)},
+				{"Total time", elapsed},
+				{"Avg query latency", avg},
+				{"Max query latency", slowest},
+				{"Connections created", atomic.LoadInt64(&counter.created)},
+				{"Handshakes completed", atomic.LoadInt64(&counter.ready)},
+				{"Peak open connections", atomic.LoadInt64(&counter.peak)},
+				{"Open connections at end", openAtEnd},
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// findOneBySource fetches one event of a random source system
+func findOneBySource(ctx context.Context, coll *mongo.Collection) error {
+	source := models.SourceSystems[rand.Intn(len(models.SourceSystems))]
+	err := coll.FindOne(ctx, bson.M{"sourceSystem": source}).Err()
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package scenarios
+
+import (
+	"fmt"
+	"time"
+
+	"mongo-bench/internal/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Name of the index created to cover the high severity query
+const coveringIndexName = "timestamp_severityLevel_eventType_sourceSystem"
+
+// CoveredQueries compares projections that still fetch every document with a truly covered query
+func CoveredQueries(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Projections that look optimized but still fetch every document")
+
+	coll, err := ctx.Collection("covered_queries")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, coll, generateEvents(ctx.Docs, 48*time.Hour)); err != nil {
+		return nil, err
+	}
+	if _, err := database.CreateEventIndexes(ctx.Ctx, coll); err != nil {
+		return nil, err
+	}
+	if _, err := coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "timestamp", Value: -1},
+			{Key: "severity.level", Value: -1},
+			{Key: "eventType", Value: 1},
+			{Key: "sourceSystem", Value: 1},
+		},
+		Options: options.Index().SetName(coveringIndexName),
+	}); err != nil {
+		return nil, err
+	}
+
+	// The filter of FindAllFieldsAntiPattern and FindWithProjectionOptimized
+	filter := bson.M{
+		"severity.level": bson.M{"$gte": 3},
+		"timestamp":      bson.M{"$gte": time.Now().Add(-24 * time.Hour)},
+	}
+
+	variants := []struct {
+		name       string
+		projection bson.M
+		hint       string
+	}{
+		{
+			name: "No projection",
+		},
+		{
+			// severity is a whole subdocument and sourceSystem is in neither kept index
+			name: "FindWithProjectionOptimized projection",
+			projection: bson.M{
+				"eventType":    1,
+				"severity":     1,
+				"timestamp":    1,
+				"sourceSystem": 1,
+				"_id":          0,
+			},
+		},
+		{
+			name: "Projection on the keys of " + database.Timestamp_EventType_SeverityLevel_Index,
+			projection: bson.M{
+				"eventType":      1,
+				"severity.level": 1,
+				"timestamp":      1,
+				"_id":            0,
+			},
+			hint: database.Timestamp_EventType_SeverityLevel_Index,
+		},
+		{
+			name: "Covered by " + coveringIndexName,
+			projection: bson.M{
+				"eventType":      1,
+				"severity.level": 1,
+				"timestamp":      1,
+				"sourceSystem":   1,
+				"_id":            0,
+			},
+			hint: coveringIndexName,
+		},
+	}
+
+	var results []VariantResult
+	for _, v := range variants {
+		fmt.Printf("Measuring projection: %s\n", v.name)
+
+		opts := options.Find()
+		var extra []bson.E
+		if v.projection != nil {
+			opts.SetProjection(v.projection)
+			extra = append(extra, bson.E{Key: "projection", Value: v.projection})
+		}
+		if v.hint != "" {
+			opts.SetHint(v.hint)
+			extra = append(extra, bson.E{Key: "hint", Value: v.hint})
+		}
+
+		latency, found, err := countFind(ctx.Ctx, coll, filter, opts)
+		if err != nil {
+			return results, err
+		}
+		stats, err := explainFind(ctx.Ctx, coll, filter, extra...)
+		if err != nil {
+			return results, err
+		}
+
+		result := VariantResult{
+			Scenario: "CoveredQueries",
+			Variant:  v.name,
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Latency", latency},
+				{"Events found", found},
+				{"Covered", stats.DocsExamined == 0 && found > 0},
+			},
+		}
+		result.Metrics = append(result.Metrics, explainMetrics(stats)...)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
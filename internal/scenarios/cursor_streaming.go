@@ -0,0 +1,183 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"mongo-bench/internal/database"
+	"mongo-bench/internal/models"
+	"mongo-bench/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// How often the heap is sampled while a cursor is read
+const heapSampleInterval = 5 * time.Millisecond
+
+// CursorStreaming compares reading a large result with cursor.All against streaming it with
+// cursor.Next at different batch sizes, decoding into models.Event or keeping bson.Raw
+func CursorStreaming(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Loading large results with cursor.All")
+
+	seeded, err := ctx.Collection("cursor_streaming")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, seeded, generateEvents(ctx.Docs, 24*time.Hour)); err != nil {
+		return nil, err
+	}
+
+	// A dedicated client counts the getMore round trips each variant needs
+	var getMores int64
+	client, err := database.ConnectMongoDB(ctx.Ctx, ctx.Config, options.Client().SetMonitor(&event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			if e.CommandName == "getMore" {
+				atomic.AddInt64(&getMores, 1)
+			}
+		},
+	}))
+	if err != nil {
+		return nil, err
+	}
+	defer client.Disconnect(ctx.Ctx)
+	coll := client.Database(ctx.Database.Name()).Collection(seeded.Name())
+
+	variants := []struct {
+		name      string
+		all       bool
+		raw       bool
+		batchSize int32
+	}{
+		{"cursor.All into []models.Event", true, false, 0},
+		{"cursor.All into []bson.Raw", true, true, 0},
+		{"cursor.Next into models.Event, default batch size", false, false, 0},
+		{"cursor.Next into models.Event, batch size 10", false, false, 10},
+		{"cursor.Next into models.Event, batch size 1000", false, false, 1000},
+		{"cursor.Next with bson.Raw, batch size 1000", false, true, 1000},
+	}
+
+	var results []VariantResult
+	for _, v := range variants {
+		fmt.Printf("Measuring cursor: %s\n", v.name)
+
+		opts := options.Find()
+		if v.batchSize > 0 {
+			opts.SetBatchSize(v.batchSize)
+		}
+
+		atomic.StoreInt64(&getMores, 0)
+		var (
+			read       int
+			firstDoc   time.Duration
+			begin      time.Time
+			peak, base uint64
+		)
+		stop := sampleHeap(&peak, &base)
+		profile, err := utils.ProfileFunc(v.name, func() error {
+			begin = time.Now()
+			cursor, err := coll.Find(ctx.Ctx, bson.M{}, opts)
+			if err != nil {
+				return err
+			}
+			defer cursor.Close(ctx.Ctx)
+
+			if v.all {
+				// Nothing reaches the caller until the whole result has been decoded
+				if v.raw {
+					var docs []bson.Raw
+					err = cursor.All(ctx.Ctx, &docs)
+					read = len(docs)
+				} else {
+					var docs []models.Event
+					err = cursor.All(ctx.Ctx, &docs)
+					read = len(docs)
+				}
+				firstDoc = time.Since(begin)
+				return err
+			}
+
+			for cursor.Next(ctx.Ctx) {
+				if read == 0 {
+					firstDoc = time.Since(begin)
+				}
+				read++
+
+				if v.raw {
+					if _, err := cursor.Current.LookupErr("eventType"); err != nil {
+						return err
+					}
+					continue
+				}
+				var e models.Event
+				if err := cursor.Decode(&e); err != nil {
+					return err
+				}
+			}
+			return cursor.Err()
+		})
+		stop()
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, VariantResult{
+			Scenario: "CursorStreaming",
+			Variant:  v.name,
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Documents read", read},
+				{"Total time", profile.ExecutionTime},
+				{"Time to first document", firstDoc},
+				{"getMore round trips", atomic.LoadInt64(&getMores)},
+				{"Peak heap growth", formatMB(int64(peak - base))},
+				{"Memory allocated", formatMB(int64(profile.MemoryUsage))},
+			},
+		})
+	}
+
+	return results, nil
+}
+
+// sampleHeap records the heap in use after a GC as base and the highest heap seen as peak
+// until the returned function is called
+func sampleHeap(peak, base *uint64) func() {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	*base = stats.HeapAlloc
+	*peak = stats.HeapAlloc
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(heapSampleInterval)
+		defer ticker.Stop()
+
+		var stats runtime.MemStats
+		for {
+			runtime.ReadMemStats(&stats)
+			if stats.HeapAlloc > *peak {
+				*peak = stats.HeapAlloc
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
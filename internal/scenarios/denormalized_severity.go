@@ -0,0 +1,174 @@
+package scenarios
+
+import (
+	"fmt"
+	"time"
+
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// Severity level renamed by both variants
+	renamedSeverityLevel = 3
+	// Number of most recent events read per page
+	severityPageSize = 1000
+)
+
+// DenormalizedSeverity compares renaming a severity level embedded in every event with renaming
+// it in a severityLevels reference collection that is joined at read time
+func DenormalizedSeverity(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Embedding lookup data that can change in every document")
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+
+	embedded, err := ctx.Collection("severity_embedded")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, embedded, events); err != nil {
+		return nil, err
+	}
+
+	// The referencing events keep only the level, label and color live in severityLevels
+	referencing, err := ctx.Collection("severity_referencing")
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		doc, err := eventDoc(e)
+		if err != nil {
+			return nil, err
+		}
+		for i := range doc {
+			if doc[i].Key == "severity" {
+				doc[i].Value = bson.M{"level": e.Severity.Level}
+			}
+		}
+		docs = append(docs, doc)
+	}
+	if err := insertBatches(ctx.Ctx, referencing, docs); err != nil {
+		return nil, err
+	}
+
+	levels, err := ctx.Collection("severityLevels")
+	if err != nil {
+		return nil, err
+	}
+	levelDocs := make([]interface{}, 0, len(models.SeverityLevels))
+	for _, level := range models.SeverityLevels {
+		levelDocs = append(levelDocs, bson.M{"_id": level.Level, "label": level.Label, "color": level.Color})
+	}
+	if err := insertBatches(ctx.Ctx, levels, levelDocs); err != nil {
+		return nil, err
+	}
+
+	for _, coll := range []*mongo.Collection{embedded, referencing} {
+		if _, err := coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "timestamp", Value: -1}},
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	rename := bson.M{"label": "Major", "color": "amber"}
+	recent := mongo.Pipeline{
+		{{Key: "$sort", Value: bson.M{"timestamp": -1}}},
+		{{Key: "$limit", Value: severityPageSize}},
+	}
+
+	variants := []struct {
+		name string
+		coll *mongo.Collection
+		// rename applies the new label and color and returns the number of documents written
+		rename func() (int64, error)
+		read   mongo.Pipeline
+	}{
+		{
+			name: "Embedded label and color",
+			coll: embedded,
+			rename: func() (int64, error) {
+				res, err := embedded.UpdateMany(ctx.Ctx,
+					bson.M{"severity.level": renamedSeverityLevel},
+					bson.M{"$set": bson.M{"severity.label": rename["label"], "severity.color": rename["color"]}})
+				if err != nil {
+					return 0, err
+				}
+				return res.ModifiedCount, nil
+			},
+			read: recent,
+		},
+		{
+			name: "Referenced severityLevels with $lookup",
+			coll: referencing,
+			rename: func() (int64, error) {
+				res, err := levels.UpdateOne(ctx.Ctx,
+					bson.M{"_id": renamedSeverityLevel},
+					bson.M{"$set": rename})
+				if err != nil {
+					return 0, err
+				}
+				return res.ModifiedCount, nil
+			},
+			read: append(recent[:len(recent):len(recent)],
+				bson.D{{Key: "$lookup", Value: bson.M{
+					"from":         levels.Name(),
+					"localField":   "severity.level",
+					"foreignField": "_id",
+					"as":           "severityLevel",
+				}}},
+				bson.D{{Key: "$unwind", Value: "$severityLevel"}},
+			),
+		},
+	}
+
+	var results []VariantResult
+	for _, v := range variants {
+		fmt.Printf("Measuring severity rename: %s\n", v.name)
+
+		begin := time.Now()
+		written, err := v.rename()
+		if err != nil {
+			return results, err
+		}
+		renameLatency := time.Since(begin)
+
+		begin = time.Now()
+		read, err := countAggregate(ctx, v.coll, v.read)
+		if err != nil {
+			return results, err
+		}
+		readLatency := time.Since(begin)
+
+		explain, err := explainAggregate(ctx.Ctx, v.coll, v.read)
+		if err != nil {
+			return results, err
+		}
+
+		stats, err := getCollectionStats(ctx.Ctx, v.coll)
+		if err != nil {
+			return results, err
+		}
+
+		result := VariantResult{
+			Scenario: "DenormalizedSeverity",
+			Variant:  v.name,
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Rename latency", renameLatency},
+				{"Documents written by rename", written},
+				{fmt.Sprintf("Read latency (%d most recent)", severityPageSize), readLatency},
+				{"Events read", read},
+				{"Read plan", explain.PlanShape},
+				{"Events collection size", formatMB(stats.Size)},
+			},
+		}
+		result.Metrics = append(result.Metrics, lookupMetrics(explain)...)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,66 @@
+package scenarios
+
+import (
+	"errors"
+
+	"mongo-bench/internal/utils"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Server error codes grouped by the outcome they represent
+var (
+	// BSONObjectTooLarge, and the update variant raised when a $push overflows 16MB
+	documentTooLargeCodes = []int{10334, 17419}
+	// ExceededMemoryLimit, QueryExceededMemoryLimitNoDiskUseAllowed and the legacy $group/$sort limits
+	memoryLimitCodes = []int{146, 292, 16945, 16819}
+	// InvalidPipelineOperator, unknown group operator and unrecognized pipeline stage
+	unsupportedCodes = []int{168, 15952, 40324}
+	// IDLUnknownField and InvalidOptions, raised by servers that do not know the timeseries option
+	timeSeriesUnsupportedCodes = []int{40415, 72}
+	// AtlasError, raised by Atlas tiers that do not allow server-side JavaScript
+	javaScriptDisabledCodes = []int{8000}
+)
+
+// BadValue is raised for many invalid queries; only this message means scripting is disabled
+const (
+	badValueCode          = 2
+	noScriptEngineMessage = "no globalScriptEngine"
+)
+
+// classifyError maps a server error or an expired deadline to a variant outcome.
+// It returns false when err is neither, which should fail the scenario instead.
+func classifyError(err error) (Outcome, bool) {
+	if err == nil {
+		return OutcomeOK, true
+	}
+	if utils.IsTimedOut(err) {
+		return OutcomeTimedOut, true
+	}
+
+	var se mongo.ServerError
+	if !errors.As(err, &se) {
+		return "", false
+	}
+
+	switch {
+	case hasAnyErrorCode(se, documentTooLargeCodes):
+		return OutcomeDocumentTooLarge, true
+	case hasAnyErrorCode(se, memoryLimitCodes):
+		return OutcomeMemoryLimit, true
+	case hasAnyErrorCode(se, unsupportedCodes):
+		return OutcomeUnsupported, true
+	default:
+		return OutcomeServerError, true
+	}
+}
+
+// hasAnyErrorCode reports whether the server error carries one of the codes
+func hasAnyErrorCode(se mongo.ServerError, codes []int) bool {
+	for _, code := range codes {
+		if se.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
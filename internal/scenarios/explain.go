@@ -0,0 +1,194 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ExplainStats holds the execution statistics of an explained command
+type ExplainStats struct {
+	KeysExamined  int64
+	DocsExamined  int64
+	Returned      int64
+	ExecutionTime time.Duration
+	PlanShape     string
+	// Lookup holds the $lookup stages the query engine did not absorb, if any
+	Lookup *LookupStats
+}
+
+// LookupStats sums the execution statistics of $lookup stages run outside the query engine
+type LookupStats struct {
+	DocsExamined    int64
+	KeysExamined    int64
+	CollectionScans int64
+	IndexesUsed     []string
+}
+
+// explainFind explains a find with the given filter in executionStats verbosity.
+// Extra find fields such as projection or hint can be appended.
+func explainFind(ctx context.Context, coll *mongo.Collection, filter interface{}, extra ...bson.E) (ExplainStats, error) {
+	cmd := bson.D{
+		{Key: "find", Value: coll.Name()},
+		{Key: "filter", Value: filter},
+	}
+	return explainCommand(ctx, coll.Database(), append(cmd, extra...))
+}
+
+// explainAggregate explains an aggregation pipeline in executionStats verbosity
+func explainAggregate(ctx context.Context, coll *mongo.Collection, pipeline mongo.Pipeline) (ExplainStats, error) {
+	return explainCommand(ctx, coll.Database(), bson.D{
+		{Key: "aggregate", Value: coll.Name()},
+		{Key: "pipeline", Value: pipeline},
+		{Key: "cursor", Value: bson.M{}},
+	})
+}
+
+// explainCommand explains an arbitrary command in executionStats verbosity
+func explainCommand(ctx context.Context, db *mongo.Database, cmd bson.D) (ExplainStats, error) {
+	var raw bson.M
+	err := db.RunCommand(ctx, bson.D{
+		{Key: "explain", Value: cmd},
+		{Key: "verbosity", Value: "executionStats"},
+	}).Decode(&raw)
+	if err != nil {
+		return ExplainStats{}, err
+	}
+
+	var stats ExplainStats
+
+	// Aggregations that cannot be pushed down entirely report their cursor stage under "stages",
+	// followed by the remaining stages such as $lookup with their own statistics
+	if stages, ok := raw["stages"].(bson.A); ok && len(stages) > 0 {
+		stats.Lookup = lookupStats(stages)
+		if first, ok := stages[0].(bson.M); ok {
+			if cursorStage, ok := first["$cursor"].(bson.M); ok {
+				raw = cursorStage
+			}
+		}
+	}
+
+	if execStats, ok := raw["executionStats"].(bson.M); ok {
+		stats.KeysExamined = toInt64(execStats["totalKeysExamined"])
+		stats.DocsExamined = toInt64(execStats["totalDocsExamined"])
+		stats.Returned = toInt64(execStats["nReturned"])
+		stats.ExecutionTime = time.Duration(toInt64(execStats["executionTimeMillis"])) * time.Millisecond
+	}
+	if planner, ok := raw["queryPlanner"].(bson.M); ok {
+		if plan, ok := planner["winningPlan"].(bson.M); ok {
+			// Plans executed by the slot based engine nest the classic plan under queryPlan
+			if queryPlan, ok := plan["queryPlan"].(bson.M); ok {
+				plan = queryPlan
+			}
+			stats.PlanShape = planShape(plan)
+		}
+	}
+
+	return stats, nil
+}
+
+// lookupStats sums the statistics of the $lookup stages in an aggregation explain, or returns nil without any
+func lookupStats(stages bson.A) *LookupStats {
+	var stats *LookupStats
+	for _, s := range stages {
+		stage, ok := s.(bson.M)
+		if !ok {
+			continue
+		}
+		if _, ok := stage["$lookup"]; !ok {
+			continue
+		}
+
+		if stats == nil {
+			stats = &LookupStats{}
+		}
+		stats.DocsExamined += toInt64(stage["totalDocsExamined"])
+		stats.KeysExamined += toInt64(stage["totalKeysExamined"])
+		stats.CollectionScans += toInt64(stage["collectionScans"])
+		if indexes, ok := stage["indexesUsed"].(bson.A); ok {
+			for _, index := range indexes {
+				if name, ok := index.(string); ok {
+					stats.IndexesUsed = append(stats.IndexesUsed, name)
+				}
+			}
+		}
+	}
+	return stats
+}
+
+// planShape renders a winning plan as nested stages, e.g. FETCH(IXSCAN[timestamp_severityLevel])
+func planShape(stage bson.M) string {
+	name, _ := stage["stage"].(string)
+	if index, ok := stage["indexName"].(string); ok {
+		name = fmt.Sprintf("%s[%s]", name, index)
+	}
+	// $lookup stages pushed down to the query engine name their join strategy
+	if strategy, ok := stage["strategy"].(string); ok {
+		name = fmt.Sprintf("%s<%s>", name, strategy)
+	}
+
+	var children []string
+	if input, ok := stage["inputStage"].(bson.M); ok {
+		children = append(children, planShape(input))
+	}
+	if inputs, ok := stage["inputStages"].(bson.A); ok {
+		for _, in := range inputs {
+			if input, ok := in.(bson.M); ok {
+				children = append(children, planShape(input))
+			}
+		}
+	}
+
+	if len(children) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(children, ", "))
+}
+
+// explainMetrics turns explain statistics into variant metrics
+func explainMetrics(stats ExplainStats) []Metric {
+	return []Metric{
+		{"Keys examined", stats.KeysExamined},
+		{"Docs examined", stats.DocsExamined},
+		{"Returned", stats.Returned},
+		{"Server execution time", stats.ExecutionTime},
+		{"Plan", stats.PlanShape},
+	}
+}
+
+// lookupMetrics turns $lookup stage statistics into variant metrics; it returns none when
+// the lookup ran inside the query engine and is already part of the plan
+func lookupMetrics(stats ExplainStats) []Metric {
+	if stats.Lookup == nil {
+		return nil
+	}
+
+	indexes := "none"
+	if len(stats.Lookup.IndexesUsed) > 0 {
+		indexes = strings.Join(stats.Lookup.IndexesUsed, ", ")
+	}
+	return []Metric{
+		{"$lookup docs examined", stats.Lookup.DocsExamined},
+		{"$lookup keys examined", stats.Lookup.KeysExamined},
+		{"$lookup collection scans", stats.Lookup.CollectionScans},
+		{"$lookup indexes used", indexes},
+	}
+}
+
+// toInt64 converts the numeric types returned by the server to int64
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	default:
+		return 0
+	}
+}
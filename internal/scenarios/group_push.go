@@ -0,0 +1,194 @@
+package scenarios
+
+import (
+	"fmt"
+	"time"
+
+	"mongo-bench/internal/models"
+	"mongo-bench/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Number of documents kept per group by the bounded variants
+	groupTopN = 10
+	// Largest BSON document the server returns
+	maxDocumentBytes = 16 * 1024 * 1024
+)
+
+// GroupPushRoot compares pushing whole documents into $group with projected and bounded accumulators
+func GroupPushRoot(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Pushing whole documents into every $group")
+
+	coll, err := ctx.Collection("group_push")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, coll, generateEvents(ctx.Docs, 24*time.Hour)); err != nil {
+		return nil, err
+	}
+
+	// Pushing $$ROOT only overflows once the average group outgrows the 16MB document limit
+	count, avgSize, _, err := documentSizes(ctx.Ctx, coll)
+	if err != nil {
+		return nil, err
+	}
+	groupBytes := float64(count) / float64(len(models.EventTypes)) * avgSize
+	tooLargeAt := int64(0)
+	if avgSize > 0 {
+		tooLargeAt = int64(maxDocumentBytes / avgSize * float64(len(models.EventTypes)))
+	}
+	pushRootExpected := OutcomeOK
+	if groupBytes >= maxDocumentBytes {
+		pushRootExpected = OutcomeDocumentTooLarge
+	}
+
+	// One bounded sub-pipeline per event type
+	facets := bson.M{}
+	for _, eventType := range models.EventTypes {
+		facets[eventType] = bson.A{
+			bson.M{"$match": bson.M{"eventType": eventType}},
+			bson.M{"$sort": bson.M{"timestamp": -1}},
+			bson.M{"$limit": groupTopN},
+		}
+	}
+
+	variants := []struct {
+		name         string
+		pipeline     mongo.Pipeline
+		allowDiskUse bool
+		pushesRoot   bool
+		expected     Outcome
+	}{
+		{
+			name: "$push $$ROOT",
+			pipeline: mongo.Pipeline{
+				{{Key: "$group", Value: bson.M{
+					"_id":    "$eventType",
+					"count":  bson.M{"$sum": 1},
+					"events": bson.M{"$push": "$$ROOT"},
+				}}},
+			},
+			pushesRoot: true,
+			expected:   pushRootExpected,
+		},
+		{
+			name: "$push $$ROOT with allowDiskUse",
+			pipeline: mongo.Pipeline{
+				{{Key: "$group", Value: bson.M{
+					"_id":    "$eventType",
+					"count":  bson.M{"$sum": 1},
+					"events": bson.M{"$push": "$$ROOT"},
+				}}},
+			},
+			allowDiskUse: true,
+			pushesRoot:   true,
+			expected:     pushRootExpected,
+		},
+		{
+			name: "$push projected fields",
+			pipeline: mongo.Pipeline{
+				{{Key: "$group", Value: bson.M{
+					"_id":   "$eventType",
+					"count": bson.M{"$sum": 1},
+					"events": bson.M{"$push": bson.M{
+						"timestamp": "$timestamp",
+						"level":     "$severity.level",
+					}},
+				}}},
+			},
+			expected: OutcomeOK,
+		},
+		{
+			name: "$sort then $firstN",
+			pipeline: mongo.Pipeline{
+				{{Key: "$sort", Value: bson.M{"timestamp": -1}}},
+				{{Key: "$group", Value: bson.M{
+					"_id":    "$eventType",
+					"count":  bson.M{"$sum": 1},
+					"events": bson.M{"$firstN": bson.M{"input": "$$ROOT", "n": groupTopN}},
+				}}},
+			},
+			expected: OutcomeOK,
+		},
+		{
+			name: "$topN",
+			pipeline: mongo.Pipeline{
+				{{Key: "$group", Value: bson.M{
+					"_id":   "$eventType",
+					"count": bson.M{"$sum": 1},
+					"events": bson.M{"$topN": bson.M{
+						"n":      groupTopN,
+						"sortBy": bson.M{"timestamp": -1},
+						"output": "$$ROOT",
+					}},
+				}}},
+			},
+			expected: OutcomeOK,
+		},
+		{
+			name: "$facet with $limit",
+			pipeline: mongo.Pipeline{
+				{{Key: "$facet", Value: facets}},
+			},
+			expected: OutcomeOK,
+		},
+	}
+
+	var results []VariantResult
+	for _, v := range variants {
+		fmt.Printf("Measuring aggregation: %s\n", v.name)
+
+		var groups, resultBytes int
+		profile, err := utils.ProfileFunc(v.name, func() error {
+			opts := options.Aggregate().SetAllowDiskUse(v.allowDiskUse)
+			cursor, err := coll.Aggregate(ctx.Ctx, v.pipeline, opts)
+			if err != nil {
+				return err
+			}
+			defer cursor.Close(ctx.Ctx)
+
+			for cursor.Next(ctx.Ctx) {
+				groups++
+				resultBytes += len(cursor.Current)
+			}
+			return cursor.Err()
+		})
+
+		outcome, ok := classifyError(err)
+		if !ok {
+			return results, err
+		}
+
+		result := VariantResult{
+			Scenario: "GroupPushRoot",
+			Variant:  v.name,
+			Outcome:  outcome,
+			Metrics: []Metric{
+				{"Expected outcome", v.expected},
+				{"Execution time", profile.ExecutionTime},
+			},
+		}
+		if v.pushesRoot {
+			result.Metrics = append(result.Metrics,
+				Metric{"Estimated group size", formatMB(int64(groupBytes))},
+				Metric{"Document too large expected at", fmt.Sprintf(">= %d docs", tooLargeAt)},
+			)
+		}
+		if err != nil {
+			result.Metrics = append(result.Metrics, Metric{"Error", err.Error()})
+		} else {
+			result.Metrics = append(result.Metrics,
+				Metric{"Result documents", groups},
+				Metric{"Result size", formatMB(int64(resultBytes))},
+				Metric{"Client memory", formatMB(int64(profile.MemoryUsage))},
+			)
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
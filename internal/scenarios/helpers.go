@@ -0,0 +1,207 @@
+package scenarios
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"mongo-bench/internal/models"
+	"mongo-bench/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// generateEvents generates n random events with timestamps spread over the given span
+func generateEvents(n int, span time.Duration) []models.Event {
+	now := time.Now()
+	events := make([]models.Event, 0, n)
+	for i := 0; i < n; i++ {
+		e := utils.GenerateRandomEvent()
+		e.Timestamp = now.Add(-time.Duration(rand.Int63n(int64(span))))
+		events = append(events, e)
+	}
+	return events
+}
+
+// seedEvents inserts generated events into the collection in batches
+func seedEvents(ctx context.Context, coll *mongo.Collection, events []models.Event) error {
+	docs := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		docs = append(docs, e)
+	}
+	return insertBatches(ctx, coll, docs)
+}
+
+// eventDoc converts an event into an ordered document that fields can be added to or removed from
+func eventDoc(e models.Event) (bson.D, error) {
+	raw, err := bson.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// insertBatches inserts arbitrary documents into the collection in batches
+func insertBatches(ctx context.Context, coll *mongo.Collection, docs []interface{}) error {
+	const batchSize = 1000
+
+	for start := 0; start < len(docs); start += batchSize {
+		end := start + batchSize
+		if end > len(docs) {
+			end = len(docs)
+		}
+		if _, err := coll.InsertMany(ctx, docs[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countFind runs a find and returns its client-side latency and the number of documents returned
+func countFind(ctx context.Context, coll *mongo.Collection, filter interface{}, opts ...*options.FindOptions) (time.Duration, int, error) {
+	begin := time.Now()
+	cursor, err := coll.Find(ctx, filter, opts...)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	n := 0
+	for cursor.Next(ctx) {
+		n++
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, 0, err
+	}
+	return time.Since(begin), n, nil
+}
+
+// documentSizes reports the document count and the average and largest BSON document size
+func documentSizes(ctx context.Context, coll *mongo.Collection) (int64, float64, int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"count":   bson.M{"$sum": 1},
+			"avgSize": bson.M{"$avg": bson.M{"$bsonSize": "$$ROOT"}},
+			"maxSize": bson.M{"$max": bson.M{"$bsonSize": "$$ROOT"}},
+		}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Count   int64   `bson:"count"`
+		AvgSize float64 `bson:"avgSize"`
+		MaxSize int64   `bson:"maxSize"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return 0, 0, 0, err
+	}
+	if len(results) == 0 {
+		return 0, 0, 0, nil
+	}
+
+	return results[0].Count, results[0].AvgSize, results[0].MaxSize, nil
+}
+
+// collectionStats holds the storage statistics of a collection
+type collectionStats struct {
+	Count          int64
+	Size           int64
+	StorageSize    int64
+	TotalIndexSize int64
+	IndexSizes     map[string]int64
+}
+
+// getCollectionStats reads the storage statistics of a collection through $collStats
+func getCollectionStats(ctx context.Context, coll *mongo.Collection) (collectionStats, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$collStats", Value: bson.M{"storageStats": bson.M{}}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return collectionStats{}, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		return collectionStats{}, err
+	}
+
+	stats := collectionStats{IndexSizes: make(map[string]int64)}
+	if len(results) == 0 {
+		return stats, nil
+	}
+
+	storage, _ := results[0]["storageStats"].(bson.M)
+	stats.Count = toInt64(storage["count"])
+	stats.Size = toInt64(storage["size"])
+	stats.StorageSize = toInt64(storage["storageSize"])
+	stats.TotalIndexSize = toInt64(storage["totalIndexSize"])
+	if sizes, ok := storage["indexSizes"].(bson.M); ok {
+		for name, size := range sizes {
+			stats.IndexSizes[name] = toInt64(size)
+		}
+	}
+
+	return stats, nil
+}
+
+// isReplicaSet reports whether the server is a replica set member or mongos,
+// which change streams and transactions require
+func isReplicaSet(ctx *ScenarioContext) (bool, error) {
+	var hello bson.M
+	err := ctx.Client.Database("admin").RunCommand(ctx.Ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false, err
+	}
+
+	_, isMember := hello["setName"]
+	return isMember || hello["msg"] == "isdbgrid", nil
+}
+
+// readOpcounters returns the server-wide operation counters from serverStatus
+func readOpcounters(ctx *ScenarioContext) (map[string]int64, error) {
+	var status bson.M
+	err := ctx.Client.Database("admin").RunCommand(ctx.Ctx, bson.D{{Key: "serverStatus", Value: 1}}).Decode(&status)
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]int64)
+	if opcounters, ok := status["opcounters"].(bson.M); ok {
+		for name, value := range opcounters {
+			counters[name] = toInt64(value)
+		}
+	}
+	return counters, nil
+}
+
+// latencySummary returns the average and the largest of the measured latencies
+func latencySummary(latencies []time.Duration) (time.Duration, time.Duration) {
+	if len(latencies) == 0 {
+		return 0, 0
+	}
+
+	var total, slowest time.Duration
+	for _, l := range latencies {
+		total += l
+		if l > slowest {
+			slowest = l
+		}
+	}
+	return total / time.Duration(len(latencies)), slowest
+}
@@ -0,0 +1,139 @@
+package scenarios
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexKeyOrder builds indexes with different key orders over status, severity.level,
+// eventType and timestamp and runs the same queries against each of them
+func IndexKeyOrder(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Leading indexes with low-cardinality fields by reflex")
+
+	coll, err := ctx.Collection("index_key_order")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, coll, generateEvents(ctx.Docs, 24*time.Hour)); err != nil {
+		return nil, err
+	}
+
+	indexes := []struct {
+		name string
+		keys bson.D
+	}{
+		{"status_severityLevel_eventType_timestamp", bson.D{
+			{Key: "status", Value: 1},
+			{Key: "severity.level", Value: 1},
+			{Key: "eventType", Value: 1},
+			{Key: "timestamp", Value: -1},
+		}},
+		{"severityLevel_status_timestamp", bson.D{
+			{Key: "severity.level", Value: 1},
+			{Key: "status", Value: 1},
+			{Key: "timestamp", Value: -1},
+		}},
+		{"status_timestamp", bson.D{
+			{Key: "status", Value: 1},
+			{Key: "timestamp", Value: -1},
+		}},
+		{"eventType_status_timestamp", bson.D{
+			{Key: "eventType", Value: 1},
+			{Key: "status", Value: 1},
+			{Key: "timestamp", Value: -1},
+		}},
+		{"timestamp_status_severityLevel", bson.D{
+			{Key: "timestamp", Value: -1},
+			{Key: "status", Value: 1},
+			{Key: "severity.level", Value: 1},
+		}},
+	}
+
+	indexModels := make([]mongo.IndexModel, 0, len(indexes))
+	for _, index := range indexes {
+		indexModels = append(indexModels, mongo.IndexModel{
+			Keys:    index.keys,
+			Options: options.Index().SetName(index.name),
+		})
+	}
+	if _, err := coll.Indexes().CreateMany(ctx.Ctx, indexModels); err != nil {
+		return nil, err
+	}
+
+	lastHour := bson.M{"$gte": time.Now().Add(-time.Hour)}
+	queries := []struct {
+		name   string
+		filter bson.M
+	}{
+		{"Unhandled in the last hour", bson.M{
+			"status":    "Unhandled",
+			"timestamp": lastHour,
+		}},
+		{"Severity >= 3 in the last hour", bson.M{
+			"severity.level": bson.M{"$gte": 3},
+			"timestamp":      lastHour,
+		}},
+		{"API errors in progress", bson.M{
+			"eventType": "API Error",
+			"status":    "In Progress",
+		}},
+		{"Critical open events in the last hour", bson.M{
+			"status":         bson.M{"$in": bson.A{"Unhandled", "In Progress"}},
+			"severity.level": 4,
+			"timestamp":      lastHour,
+		}},
+	}
+
+	stats, err := getCollectionStats(ctx.Ctx, coll)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []VariantResult
+
+	// Force every index onto every query to compare the work each key order causes
+	for _, index := range indexes {
+		fmt.Printf("Measuring index: %s\n", index.name)
+
+		result := VariantResult{
+			Scenario: "IndexKeyOrder",
+			Variant:  index.name,
+			Outcome:  OutcomeOK,
+			Metrics:  []Metric{{"Index size", formatMB(stats.IndexSizes[index.name])}},
+		}
+		for _, q := range queries {
+			explain, err := explainFind(ctx.Ctx, coll, q.filter, bson.E{Key: "hint", Value: index.name})
+			if err != nil {
+				return results, err
+			}
+			result.Metrics = append(result.Metrics, Metric{
+				q.name,
+				fmt.Sprintf("%d keys, %d docs examined, %d returned",
+					explain.KeysExamined, explain.DocsExamined, explain.Returned),
+			})
+		}
+		results = append(results, result)
+	}
+
+	// Then let the planner pick among all of them
+	for _, q := range queries {
+		explain, err := explainFind(ctx.Ctx, coll, q.filter)
+		if err != nil {
+			return results, err
+		}
+
+		result := VariantResult{
+			Scenario: "IndexKeyOrder",
+			Variant:  "Planner choice: " + q.name,
+			Outcome:  OutcomeOK,
+		}
+		result.Metrics = append(result.Metrics, explainMetrics(explain)...)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
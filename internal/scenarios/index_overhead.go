@@ -0,0 +1,141 @@
+package scenarios
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"mongo-bench/internal/database"
+	"mongo-bench/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// IndexOverhead measures insert throughput and storage as the number of indexes grows,
+// then reports which indexes the query suite never used
+func IndexOverhead(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Adding indexes until every write pays for them")
+
+	// The kept event indexes first, then the kind of single-field indexes that pile up over time
+	candidates := database.EventIndexes()
+	for _, field := range []string{
+		"eventType",
+		"severity.level",
+		"status",
+		"sourceSystem",
+		"sourceIp",
+		"assignedTo",
+		"tags",
+		"affectedComponents",
+		"metadata.eventId",
+	} {
+		candidates = append(candidates, mongo.IndexModel{Keys: bson.D{{Key: field, Value: 1}}})
+	}
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+
+	var results []VariantResult
+	var coll *mongo.Collection
+	for k := 0; k <= len(candidates); k++ {
+		var err error
+		coll, err = ctx.Collection("index_overhead")
+		if err != nil {
+			return results, err
+		}
+		if k > 0 {
+			if _, err := coll.Indexes().CreateMany(ctx.Ctx, candidates[:k]); err != nil {
+				return results, err
+			}
+		}
+
+		// The _id index is always there
+		fmt.Printf("Inserting %d events with %d indexes\n", len(events), k+1)
+
+		begin := time.Now()
+		if err := seedEvents(ctx.Ctx, coll, events); err != nil {
+			return results, err
+		}
+		elapsed := time.Since(begin)
+
+		stats, err := getCollectionStats(ctx.Ctx, coll)
+		if err != nil {
+			return results, err
+		}
+
+		results = append(results, VariantResult{
+			Scenario: "IndexOverhead",
+			Variant:  fmt.Sprintf("%d indexes", k+1),
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Insert time", elapsed},
+				{"Insert throughput (docs/s)", float64(len(events)) / elapsed.Seconds()},
+				{"Data size", formatMB(stats.Size)},
+				{"Storage size", formatMB(stats.StorageSize)},
+				{"Total index size", formatMB(stats.TotalIndexSize)},
+			},
+		})
+	}
+
+	// Run the query suite against the fully indexed collection and see which indexes it touched
+	fmt.Println("Running the query suite against the fully indexed collection")
+	queryContext := &utils.QueryContext{
+		Ctx:        ctx.Ctx,
+		Collection: coll,
+	}
+	for _, pair := range utils.GetQueryTestPairs() {
+		if err := pair.TestFunc(queryContext); err != nil {
+			log.Printf("Test %s failed: %v", pair.Name, err)
+		}
+	}
+
+	usage, err := indexUsage(ctx, coll)
+	if err != nil {
+		return results, err
+	}
+	results = append(results, usage)
+
+	return results, nil
+}
+
+// indexUsage reports the access count of every index through $indexStats
+func indexUsage(ctx *ScenarioContext, coll *mongo.Collection) (VariantResult, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$indexStats", Value: bson.M{}}},
+		{{Key: "$sort", Value: bson.M{"name": 1}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx.Ctx, pipeline)
+	if err != nil {
+		return VariantResult{}, err
+	}
+	defer cursor.Close(ctx.Ctx)
+
+	var stats []struct {
+		Name     string `bson:"name"`
+		Accesses struct {
+			Ops int64 `bson:"ops"`
+		} `bson:"accesses"`
+	}
+	if err = cursor.All(ctx.Ctx, &stats); err != nil {
+		return VariantResult{}, err
+	}
+
+	result := VariantResult{
+		Scenario: "IndexOverhead",
+		Variant:  "Index usage by the query suite",
+		Outcome:  OutcomeOK,
+	}
+	unused := 0
+	for _, s := range stats {
+		if s.Accesses.Ops == 0 {
+			unused++
+			result.Metrics = append(result.Metrics, Metric{s.Name, "never used"})
+			continue
+		}
+		result.Metrics = append(result.Metrics, Metric{s.Name, fmt.Sprintf("%d ops", s.Accesses.Ops)})
+	}
+	result.Metrics = append(result.Metrics, Metric{"Unused indexes", fmt.Sprintf("%d of %d", unused, len(stats))})
+
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package scenarios
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mongo-bench/internal/database"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// JavaScriptFilters compares $where and non-indexable $expr filters with native operators
+func JavaScriptFilters(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Filtering with server-side JavaScript and computed expressions")
+
+	coll, err := ctx.Collection("js_filters")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, coll, generateEvents(ctx.Docs, 48*time.Hour)); err != nil {
+		return nil, err
+	}
+	if _, err := database.CreateEventIndexes(ctx.Ctx, coll); err != nil {
+		return nil, err
+	}
+
+	since := time.Now().Add(-24 * time.Hour)
+	variants := []struct {
+		name   string
+		filter bson.M
+	}{
+		{
+			name: "$where",
+			filter: bson.M{"$where": fmt.Sprintf(
+				"this.severity.level >= 3 && this.timestamp >= new Date(%d)", since.UnixMilli())},
+		},
+		{
+			// Wrapping the fields in conversions hides them from the query planner
+			name: "Non-indexable $expr",
+			filter: bson.M{"$expr": bson.M{"$and": bson.A{
+				bson.M{"$gte": bson.A{bson.M{"$toInt": "$severity.level"}, 3}},
+				bson.M{"$gte": bson.A{bson.M{"$toLong": "$timestamp"}, since.UnixMilli()}},
+			}}},
+		},
+		{
+			name: "Native operators",
+			filter: bson.M{
+				"timestamp":      bson.M{"$gte": since},
+				"severity.level": bson.M{"$gte": 3},
+			},
+		},
+	}
+
+	var results []VariantResult
+	for _, v := range variants {
+		fmt.Printf("Measuring filter: %s\n", v.name)
+
+		result := VariantResult{
+			Scenario: "JavaScriptFilters",
+			Variant:  v.name,
+			Outcome:  OutcomeOK,
+		}
+
+		latency, found, err := countFind(ctx.Ctx, coll, v.filter)
+		if isJavaScriptDisabled(err) {
+			result.Outcome = OutcomeUnsupported
+			result.Metrics = append(result.Metrics, Metric{"Reason", err.Error()})
+			results = append(results, result)
+			continue
+		}
+		if err != nil {
+			return results, err
+		}
+
+		stats, err := explainFind(ctx.Ctx, coll, v.filter)
+		if err != nil {
+			return results, err
+		}
+
+		result.Metrics = append(result.Metrics,
+			Metric{"Latency", latency},
+			Metric{"Events found", found},
+		)
+		result.Metrics = append(result.Metrics, explainMetrics(stats)...)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// isJavaScriptDisabled reports whether the server rejected a query because server-side JavaScript is off
+func isJavaScriptDisabled(err error) bool {
+	var se mongo.ServerError
+	if !errors.As(err, &se) {
+		return false
+	}
+
+	if hasAnyErrorCode(se, javaScriptDisabledCodes) {
+		return true
+	}
+	// Errors thrown by the $where expression itself are real failures
+	return se.HasErrorCode(badValueCode) && strings.Contains(se.Error(), noScriptEngineMessage)
+}
@@ -0,0 +1,329 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Number of IDs per query when chunking a large $in list
+const inChunkSize = 1000
+
+// $in list sizes compared; the largest one passes the 16MB command size limit
+var inListSizes = []int{10, 1000, 10000, 100000, 1000000}
+
+// LargeInLists compares $in filters of growing size, chunked $in batches and a
+// client-side join with a single $lookup pipeline
+func LargeInLists(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Sending thousands of IDs collected by a first query")
+
+	events, err := ctx.Collection("in_events")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, events, generateEvents(ctx.Docs, 24*time.Hour)); err != nil {
+		return nil, err
+	}
+	ids, err := eventIDs(ctx.Ctx, events)
+	if err != nil {
+		return nil, err
+	}
+
+	// Explain does not report planning time, the profiler does
+	restore, err := enableProfiling(ctx)
+	profiled := err == nil
+	if err != nil {
+		fmt.Printf("Could not enable profiling, planning time will not be reported: %v\n", err)
+	}
+	defer func() {
+		if profiled {
+			restore()
+		}
+	}()
+
+	var results []VariantResult
+
+	// $in with a growing number of IDs, padded with IDs that match nothing
+	for _, size := range inListSizes {
+		fmt.Printf("Measuring $in with %d IDs\n", size)
+		list := idList(ids, size)
+		filter := bson.M{"_id": bson.M{"$in": list}}
+
+		result, err := measureInFilter(ctx, events, fmt.Sprintf("$in with %d IDs", size), filter, profiled)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+
+	// Keep the profiler from slowing down the remaining variants
+	if profiled {
+		restore()
+		profiled = false
+	}
+
+	// The same large list sent in chunks
+	chunked := idList(ids, 100000)
+	fmt.Printf("Measuring chunked $in with %d IDs\n", len(chunked))
+	begin := time.Now()
+	found, queries := 0, 0
+	for start := 0; start < len(chunked); start += inChunkSize {
+		end := start + inChunkSize
+		if end > len(chunked) {
+			end = len(chunked)
+		}
+		_, n, err := countFind(ctx.Ctx, events, bson.M{"_id": bson.M{"$in": chunked[start:end]}})
+		if err != nil {
+			return results, err
+		}
+		found += n
+		queries++
+	}
+	results = append(results, VariantResult{
+		Scenario: "LargeInLists",
+		Variant:  fmt.Sprintf("Chunked $in with %d IDs (%d per query)", len(chunked), inChunkSize),
+		Outcome:  OutcomeOK,
+		Metrics: []Metric{
+			{"Latency", time.Since(begin)},
+			{"Queries", queries},
+			{"Events found", found},
+		},
+	})
+
+	joinResults, err := compareJoins(ctx, events, ids)
+	results = append(results, joinResults...)
+	return results, err
+}
+
+// measureInFilter runs a find with the filter and records its size, latency, plan and,
+// when profiled, the planning time the profiler recorded for it
+func measureInFilter(ctx *ScenarioContext, coll *mongo.Collection, name string, filter bson.M, profiled bool) (VariantResult, error) {
+	raw, err := bson.Marshal(filter)
+	if err != nil {
+		return VariantResult{}, err
+	}
+
+	result := VariantResult{
+		Scenario: "LargeInLists",
+		Variant:  name,
+		Metrics:  []Metric{{"Filter size", formatMB(int64(len(raw)))}},
+	}
+
+	latency, found, err := countFind(ctx.Ctx, coll, filter)
+	outcome, ok := classifyError(err)
+	if !ok {
+		return result, err
+	}
+	result.Outcome = outcome
+	if err != nil {
+		result.Metrics = append(result.Metrics, Metric{"Error", err.Error()})
+		return result, nil
+	}
+
+	// Planning a huge $in is part of its cost; read it before explain adds its own profile entry
+	planning := interface{}("not reported by this server")
+	if profiled {
+		micros, ok, err := lastPlanningTime(ctx.Ctx, coll)
+		if err != nil {
+			return result, err
+		}
+		if ok {
+			planning = time.Duration(micros) * time.Microsecond
+		}
+	}
+
+	stats, err := explainFind(ctx.Ctx, coll, filter)
+	if err != nil {
+		return result, err
+	}
+	result.Metrics = append(result.Metrics,
+		Metric{"Latency", latency},
+		Metric{"Events found", found},
+		Metric{"Server planning time", planning},
+	)
+	result.Metrics = append(result.Metrics, explainMetrics(stats)...)
+	return result, nil
+}
+
+// enableProfiling profiles every operation on the scenario database and returns
+// a function that restores the previous profiling level
+func enableProfiling(ctx *ScenarioContext) (func(), error) {
+	var current struct {
+		Level int32 `bson:"was"`
+	}
+	err := ctx.Database.RunCommand(ctx.Ctx, bson.D{{Key: "profile", Value: -1}}).Decode(&current)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Database.RunCommand(ctx.Ctx, bson.D{{Key: "profile", Value: 2}}).Err(); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		err := ctx.Database.RunCommand(ctx.Ctx, bson.D{{Key: "profile", Value: current.Level}}).Err()
+		if err != nil {
+			log.Printf("Failed to restore profiling level to %d: %v", current.Level, err)
+		}
+	}, nil
+}
+
+// lastPlanningTime returns planningTimeMicros of the latest find the profiler recorded on the collection
+func lastPlanningTime(ctx context.Context, coll *mongo.Collection) (int64, bool, error) {
+	var entry bson.M
+	err := coll.Database().Collection("system.profile").FindOne(ctx,
+		bson.M{"ns": coll.Database().Name() + "." + coll.Name(), "op": "query"},
+		options.FindOne().SetSort(bson.M{"ts": -1}),
+	).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	micros, ok := entry["planningTimeMicros"]
+	return toInt64(micros), ok, nil
+}
+
+// compareJoins resolves tickets of one team to their events client-side and with $lookup
+func compareJoins(ctx *ScenarioContext, events *mongo.Collection, ids []primitive.ObjectID) ([]VariantResult, error) {
+	tickets, err := ctx.Collection("in_tickets")
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		docs = append(docs, bson.M{
+			"eventId": id,
+			"team":    models.TeamOptions[rand.Intn(len(models.TeamOptions))],
+		})
+	}
+	if err := insertBatches(ctx.Ctx, tickets, docs); err != nil {
+		return nil, err
+	}
+	if _, err := tickets.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "team", Value: 1}},
+	}); err != nil {
+		return nil, err
+	}
+
+	team := models.TeamOptions[0]
+	var results []VariantResult
+
+	// Client-side join: collect the event IDs, then send them back as one $in list
+	fmt.Println("Measuring client-side join")
+	begin := time.Now()
+	cursor, err := tickets.Find(ctx.Ctx, bson.M{"team": team},
+		options.Find().SetProjection(bson.M{"eventId": 1, "_id": 0}))
+	if err != nil {
+		return nil, err
+	}
+	var refs []struct {
+		EventID primitive.ObjectID `bson:"eventId"`
+	}
+	if err = cursor.All(ctx.Ctx, &refs); err != nil {
+		return nil, err
+	}
+	list := make(bson.A, 0, len(refs))
+	for _, r := range refs {
+		list = append(list, r.EventID)
+	}
+	_, found, err := countFind(ctx.Ctx, events, bson.M{"_id": bson.M{"$in": list}})
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, VariantResult{
+		Scenario: "LargeInLists",
+		Variant:  "Client-side join",
+		Outcome:  OutcomeOK,
+		Metrics: []Metric{
+			{"Latency", time.Since(begin)},
+			{"Round trips", 2},
+			{"IDs sent back", len(list)},
+			{"Events found", found},
+		},
+	})
+
+	// Single pipeline: the server resolves the references itself
+	fmt.Println("Measuring $match + $lookup pipeline")
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"team": team}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from":         events.Name(),
+			"localField":   "eventId",
+			"foreignField": "_id",
+			"as":           "event",
+		}}},
+		{{Key: "$unwind", Value: "$event"}},
+	}
+	begin = time.Now()
+	cursor, err = tickets.Aggregate(ctx.Ctx, pipeline)
+	if err != nil {
+		return results, err
+	}
+	defer cursor.Close(ctx.Ctx)
+	found = 0
+	for cursor.Next(ctx.Ctx) {
+		found++
+	}
+	if err := cursor.Err(); err != nil {
+		return results, err
+	}
+	results = append(results, VariantResult{
+		Scenario: "LargeInLists",
+		Variant:  "$match + $lookup pipeline",
+		Outcome:  OutcomeOK,
+		Metrics: []Metric{
+			{"Latency", time.Since(begin)},
+			{"Round trips", 1},
+			{"Events found", found},
+		},
+	})
+
+	return results, nil
+}
+
+// eventIDs returns the _id of every event in the collection
+func eventIDs(ctx context.Context, coll *mongo.Collection) ([]primitive.ObjectID, error) {
+	cursor, err := coll.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	return ids, nil
+}
+
+// idList returns size IDs, taking existing ones first and padding with IDs that match nothing
+func idList(ids []primitive.ObjectID, size int) bson.A {
+	list := make(bson.A, 0, size)
+	for i := 0; i < size; i++ {
+		if i < len(ids) {
+			list = append(list, ids[i])
+			continue
+		}
+		list = append(list, primitive.NewObjectID())
+	}
+	return list
+}
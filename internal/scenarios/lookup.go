@@ -0,0 +1,148 @@
+package scenarios
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Fractions of the local collection joined per run, to show how cost scales with it
+var lookupLocalFractions = []int{100, 10, 1}
+
+// LookupIndexes compares $lookup against an unindexed foreignField, an indexed one and
+// a pipeline $lookup that filters early in its sub-pipeline
+func LookupIndexes(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: $lookup on a foreign field without an index")
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+	local, err := ctx.Collection("lookup_events")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, local, events); err != nil {
+		return nil, err
+	}
+
+	// Every other event has an incident referencing it by event ID
+	incidents := make([]interface{}, 0, len(events)/2)
+	for i, e := range events {
+		if i%2 != 0 {
+			continue
+		}
+		incidents = append(incidents, bson.M{
+			"eventId":  e.Metadata["eventId"],
+			"team":     models.TeamOptions[rand.Intn(len(models.TeamOptions))],
+			"open":     rand.Intn(2) == 1,
+			"openedAt": e.Timestamp,
+		})
+	}
+
+	unindexed, err := ctx.Collection("lookup_incidents_unindexed")
+	if err != nil {
+		return nil, err
+	}
+	if err := insertBatches(ctx.Ctx, unindexed, incidents); err != nil {
+		return nil, err
+	}
+	indexed, err := ctx.Collection("lookup_incidents_indexed")
+	if err != nil {
+		return nil, err
+	}
+	if err := insertBatches(ctx.Ctx, indexed, incidents); err != nil {
+		return nil, err
+	}
+	if _, err := indexed.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "eventId", Value: 1}},
+	}); err != nil {
+		return nil, err
+	}
+
+	variants := []struct {
+		name   string
+		lookup bson.M
+	}{
+		{
+			name: "Unindexed foreignField",
+			lookup: bson.M{
+				"from":         unindexed.Name(),
+				"localField":   "metadata.eventId",
+				"foreignField": "eventId",
+				"as":           "incidents",
+			},
+		},
+		{
+			name: "Indexed foreignField",
+			lookup: bson.M{
+				"from":         indexed.Name(),
+				"localField":   "metadata.eventId",
+				"foreignField": "eventId",
+				"as":           "incidents",
+			},
+		},
+		{
+			name: "Pipeline $lookup with early $match",
+			lookup: bson.M{
+				"from": indexed.Name(),
+				"let":  bson.M{"eventId": "$metadata.eventId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$eventId", "$$eventId"}}}},
+					bson.M{"$match": bson.M{"open": true}},
+					bson.M{"$project": bson.M{"team": 1, "_id": 0}},
+				},
+				"as": "incidents",
+			},
+		},
+	}
+
+	var results []VariantResult
+	for _, v := range variants {
+		for i, fraction := range lookupLocalFractions {
+			size := len(events) / fraction
+			if size == 0 {
+				size = 1
+			}
+			name := fmt.Sprintf("%s, %d local documents", v.name, size)
+			fmt.Printf("Measuring $lookup: %s\n", name)
+
+			pipeline := mongo.Pipeline{
+				{{Key: "$limit", Value: size}},
+				{{Key: "$lookup", Value: v.lookup}},
+				{{Key: "$match", Value: bson.M{"incidents": bson.M{"$ne": bson.A{}}}}},
+			}
+
+			begin := time.Now()
+			joined, err := countAggregate(ctx, local, pipeline)
+			if err != nil {
+				return results, err
+			}
+
+			result := VariantResult{
+				Scenario: "LookupIndexes",
+				Variant:  name,
+				Outcome:  OutcomeOK,
+				Metrics: []Metric{
+					{"Latency", time.Since(begin)},
+					{"Events with incidents", joined},
+				},
+			}
+
+			// The plan shape does not change with size, so explain the cheapest run only
+			if i == 0 {
+				stats, err := explainAggregate(ctx.Ctx, local, pipeline)
+				if err != nil {
+					return results, err
+				}
+				result.Metrics = append(result.Metrics, Metric{"Plan", stats.PlanShape})
+				result.Metrics = append(result.Metrics, lookupMetrics(stats)...)
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,170 @@
+package scenarios
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// metadataLayout describes one way of storing and querying event metadata
+type metadataLayout struct {
+	name       string
+	collection string
+	indexes    []mongo.IndexModel
+	toDoc      func(e models.Event) (interface{}, error)
+	// filters keyed by query name
+	filters map[string]bson.M
+}
+
+// MetadataQueries compares unindexed metadata filters with a wildcard index and the attribute pattern
+func MetadataQueries(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Querying untyped metadata fields without an index")
+
+	asEvent := func(e models.Event) (interface{}, error) { return e, nil }
+	plainFilters := map[string]bson.M{
+		"statusCode = 503": {"metadata.statusCode": 503},
+		"cpuUsage >= 90":   {"metadata.cpuUsage": bson.M{"$gte": 90}},
+	}
+
+	layouts := []metadataLayout{
+		{
+			name:       "Unindexed metadata",
+			collection: "metadata_unindexed",
+			toDoc:      asEvent,
+			filters:    plainFilters,
+		},
+		{
+			name:       "Wildcard index",
+			collection: "metadata_wildcard",
+			indexes: []mongo.IndexModel{
+				{Keys: bson.D{{Key: "metadata.$**", Value: 1}}},
+			},
+			toDoc:   asEvent,
+			filters: plainFilters,
+		},
+		{
+			name:       "Attribute pattern",
+			collection: "metadata_attributes",
+			indexes: []mongo.IndexModel{
+				{Keys: bson.D{{Key: "attrs.k", Value: 1}, {Key: "attrs.v", Value: 1}}},
+			},
+			toDoc: toAttributeDoc,
+			filters: map[string]bson.M{
+				"statusCode = 503": {"attrs": bson.M{"$elemMatch": bson.M{"k": "statusCode", "v": 503}}},
+				"cpuUsage >= 90":   {"attrs": bson.M{"$elemMatch": bson.M{"k": "cpuUsage", "v": bson.M{"$gte": 90}}}},
+			},
+		},
+	}
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+
+	var results []VariantResult
+	for _, layout := range layouts {
+		fmt.Printf("Measuring layout: %s\n", layout.name)
+
+		coll, err := ctx.Collection(layout.collection)
+		if err != nil {
+			return results, err
+		}
+		if len(layout.indexes) > 0 {
+			if _, err := coll.Indexes().CreateMany(ctx.Ctx, layout.indexes); err != nil {
+				return results, err
+			}
+		}
+
+		docs := make([]interface{}, 0, len(events))
+		for _, e := range events {
+			doc, err := layout.toDoc(e)
+			if err != nil {
+				return results, err
+			}
+			docs = append(docs, doc)
+		}
+
+		// Indexes exist before the insert so every write pays for them
+		begin := time.Now()
+		if err := insertBatches(ctx.Ctx, coll, docs); err != nil {
+			return results, err
+		}
+		elapsed := time.Since(begin)
+
+		stats, err := getCollectionStats(ctx.Ctx, coll)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, VariantResult{
+			Scenario: "MetadataQueries",
+			Variant:  layout.name + ": writes and storage",
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Insert time", elapsed},
+				{"Insert throughput (docs/s)", float64(len(docs)) / elapsed.Seconds()},
+				{"Data size", formatMB(stats.Size)},
+				{"Total index size", formatMB(stats.TotalIndexSize)},
+			},
+		})
+
+		// Run the queries in a stable order
+		names := make([]string, 0, len(layout.filters))
+		for name := range layout.filters {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			filter := layout.filters[name]
+
+			latency, found, err := countFind(ctx.Ctx, coll, filter)
+			if err != nil {
+				return results, err
+			}
+			explain, err := explainFind(ctx.Ctx, coll, filter)
+			if err != nil {
+				return results, err
+			}
+
+			result := VariantResult{
+				Scenario: "MetadataQueries",
+				Variant:  fmt.Sprintf("%s: %s", layout.name, name),
+				Outcome:  OutcomeOK,
+				Metrics: []Metric{
+					{"Latency", latency},
+					{"Events found", found},
+				},
+			}
+			result.Metrics = append(result.Metrics, explainMetrics(explain)...)
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// toAttributeDoc replaces the metadata map of an event with a {k, v} attribute array
+func toAttributeDoc(e models.Event) (interface{}, error) {
+	doc, err := eventDoc(e)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := make(bson.A, 0, len(e.Metadata))
+	for k, v := range e.Metadata {
+		attrs = append(attrs, bson.D{{Key: "k", Value: k}, {Key: "v", Value: v}})
+	}
+
+	out := make(bson.D, 0, len(doc))
+	for _, elem := range doc {
+		if elem.Key == "metadata" {
+			continue
+		}
+		out = append(out, elem)
+	}
+	out = append(out, bson.E{Key: "attrs", Value: attrs})
+
+	return out, nil
+}
@@ -0,0 +1,121 @@
+package scenarios
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Time window every variant queries
+	negationWindow = 6 * time.Hour
+	// CannotCreateIndex error code
+	cannotCreateIndexCode = 67
+)
+
+// NegationFilters compares negation operators on status with positive $in and a partial index
+func NegationFilters(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Finding unresolved events with negation operators")
+
+	// Every status except Resolved, spelled out as a positive list
+	var unresolved bson.A
+	for _, status := range models.StatusOptions {
+		if status != "Resolved" {
+			unresolved = append(unresolved, status)
+		}
+	}
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+
+	statusColl, err := ctx.Collection("negation_status_index")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, statusColl, events); err != nil {
+		return nil, err
+	}
+	if _, err := statusColl.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}},
+	}); err != nil {
+		return nil, err
+	}
+
+	partialColl, err := ctx.Collection("negation_partial_index")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, partialColl, events); err != nil {
+		return nil, err
+	}
+	// $in inside a partial filter expression needs MongoDB 6.0 or newer
+	_, partialErr := partialColl.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "timestamp", Value: -1}},
+		Options: options.Index().
+			SetName("unresolved_timestamp").
+			SetPartialFilterExpression(bson.M{"status": bson.M{"$in": unresolved}}),
+	})
+	// CannotCreateIndex, raised by servers that do not accept $in in a partial filter expression
+	var se mongo.ServerError
+	if partialErr != nil && !(errors.As(partialErr, &se) && se.HasErrorCode(cannotCreateIndexCode)) {
+		return nil, partialErr
+	}
+
+	// Every variant asks the dashboard question "unresolved events of the last few hours",
+	// so the partial index on timestamp can serve it
+	recent := bson.M{"$gte": time.Now().Add(-negationWindow)}
+	variants := []struct {
+		name   string
+		coll   *mongo.Collection
+		filter bson.M
+		err    error
+	}{
+		{"$ne", statusColl, bson.M{"status": bson.M{"$ne": "Resolved"}, "timestamp": recent}, nil},
+		{"$nin", statusColl, bson.M{"status": bson.M{"$nin": bson.A{"Resolved"}}, "timestamp": recent}, nil},
+		{"$not", statusColl, bson.M{"status": bson.M{"$not": bson.M{"$eq": "Resolved"}}, "timestamp": recent}, nil},
+		{"Positive $in", statusColl, bson.M{"status": bson.M{"$in": unresolved}, "timestamp": recent}, nil},
+		{"Partial index on unresolved", partialColl, bson.M{"status": bson.M{"$in": unresolved}, "timestamp": recent}, partialErr},
+	}
+
+	var results []VariantResult
+	for _, v := range variants {
+		fmt.Printf("Measuring filter: %s\n", v.name)
+
+		result := VariantResult{
+			Scenario: "NegationFilters",
+			Variant:  v.name,
+			Outcome:  OutcomeOK,
+		}
+		if v.err != nil {
+			result.Outcome = OutcomeUnsupported
+			result.Metrics = append(result.Metrics, Metric{"Reason", v.err.Error()})
+			results = append(results, result)
+			continue
+		}
+
+		latency, found, err := countFind(ctx.Ctx, v.coll, v.filter)
+		if err != nil {
+			return results, err
+		}
+
+		stats, err := explainFind(ctx.Ctx, v.coll, v.filter)
+		if err != nil {
+			return results, err
+		}
+
+		result.Metrics = append(result.Metrics,
+			Metric{"Latency", latency},
+			Metric{"Events found", found},
+			Metric{"Selectivity", fmt.Sprintf("%.0f%%", 100*float64(found)/float64(len(events)))},
+		)
+		result.Metrics = append(result.Metrics, explainMetrics(stats)...)
+		results = append(results, result)
+	}
+
+	return results, nil
+}
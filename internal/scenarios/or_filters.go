@@ -0,0 +1,88 @@
+package scenarios
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OrFilters compares $or with partial and full index coverage, and same-field $or with $in
+func OrFilters(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: $or across fields where not every branch is indexed")
+
+	coll, err := ctx.Collection("or_filters")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, coll, generateEvents(ctx.Docs, 24*time.Hour)); err != nil {
+		return nil, err
+	}
+	if _, err := coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "eventType", Value: 1}},
+	}); err != nil {
+		return nil, err
+	}
+
+	var results []VariantResult
+	measure := func(name string, filter bson.M) error {
+		fmt.Printf("Measuring filter: %s\n", name)
+
+		latency, found, err := countFind(ctx.Ctx, coll, filter)
+		if err != nil {
+			return err
+		}
+		stats, err := explainFind(ctx.Ctx, coll, filter)
+		if err != nil {
+			return err
+		}
+
+		result := VariantResult{
+			Scenario: "OrFilters",
+			Variant:  name,
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Latency", latency},
+				{"Events found", found},
+			},
+		}
+		result.Metrics = append(result.Metrics, explainMetrics(stats)...)
+		results = append(results, result)
+		return nil
+	}
+
+	crossField := bson.M{"$or": bson.A{
+		bson.M{"eventType": "Security Incident"},
+		bson.M{"sourceSystem": "Authentication Service"},
+	}}
+
+	// Only eventType is indexed, so the sourceSystem branch forces a collection scan
+	if err := measure("$or with one unindexed branch", crossField); err != nil {
+		return results, err
+	}
+
+	if _, err := coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sourceSystem", Value: 1}},
+	}); err != nil {
+		return results, err
+	}
+	if err := measure("$or with every branch indexed", crossField); err != nil {
+		return results, err
+	}
+
+	sameField := bson.M{"$or": bson.A{
+		bson.M{"eventType": "Security Incident"},
+		bson.M{"eventType": "Database Exception"},
+	}}
+	if err := measure("Same-field $or", sameField); err != nil {
+		return results, err
+	}
+
+	inFilter := bson.M{"eventType": bson.M{"$in": bson.A{"Security Incident", "Database Exception"}}}
+	if err := measure("Same-field $in", inFilter); err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,74 @@
+package scenarios
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Outcome categorizes how a variant finished
+type Outcome string
+
+const (
+	OutcomeOK               Outcome = "ok"
+	OutcomeUnsupported      Outcome = "unsupported"
+	OutcomeDocumentTooLarge Outcome = "document too large"
+	OutcomeMemoryLimit      Outcome = "memory limit exceeded"
+	OutcomeTimedOut         Outcome = "timed out"
+	OutcomeServerError      Outcome = "server error"
+)
+
+// Metric is a single named measurement of a variant
+type Metric struct {
+	Name  string
+	Value interface{}
+}
+
+// VariantResult represents the measurements of one scenario variant
+type VariantResult struct {
+	Scenario string
+	Variant  string
+	Outcome  Outcome
+	Metrics  []Metric
+}
+
+// String returns a formatted string representation of VariantResult
+func (r VariantResult) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Scenario [%s] variant [%s]:", r.Scenario, r.Variant)
+	if r.Outcome != "" && r.Outcome != OutcomeOK {
+		fmt.Fprintf(&sb, "\n- Outcome: %s", r.Outcome)
+	}
+	for _, m := range r.Metrics {
+		fmt.Fprintf(&sb, "\n- %s: %s", m.Name, formatValue(m.Value))
+	}
+	return sb.String()
+}
+
+// formatValue renders metric values consistently across scenarios
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case float64:
+		return fmt.Sprintf("%.2f", val)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// formatMB renders a byte count in megabytes
+func formatMB(bytes int64) string {
+	return fmt.Sprintf("%.2f MB", float64(bytes)/(1024*1024))
+}
+
+// unsupportedResults reports every variant of a scenario as unsupported for the same reason
+func unsupportedResults(scenario string, variants []string, reason string) []VariantResult {
+	results := make([]VariantResult, 0, len(variants))
+	for _, v := range variants {
+		results = append(results, VariantResult{
+			Scenario: scenario,
+			Variant:  v,
+			Outcome:  OutcomeUnsupported,
+			Metrics:  []Metric{{"Reason", reason}},
+		})
+	}
+	return results
+}
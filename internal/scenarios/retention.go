@@ -0,0 +1,279 @@
+package scenarios
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Events older than this are expired by every retention variant
+	retentionPeriod = 24 * time.Hour
+	// How long to wait for background expiry before giving up
+	retentionTimeout = 3 * time.Minute
+	// How often to check how many expired events are left
+	retentionCheckInterval = time.Second
+	// Longest span of a time-series bucket with the default granularity; a bucket only
+	// expires once its newest event has, so expired events can outlive the cutoff by this much
+	timeSeriesBucketSpan = time.Hour
+)
+
+// retentionVariant describes one way of removing old events
+type retentionVariant struct {
+	name string
+	// create sets up the empty collection before seeding
+	create func(ctx *ScenarioContext, name string) (*mongo.Collection, error)
+	// start kicks off retention; a nil channel means the server expires events on its own
+	start func(ctx *ScenarioContext, coll *mongo.Collection, cutoff time.Time) (<-chan error, error)
+	// timeSeries marks variants whose create is rejected by servers without time-series support
+	timeSeries bool
+}
+
+// Retention compares a batch DeleteMany job with a TTL index and time-series collection expiry
+func Retention(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Deleting old events with a manual batch job")
+
+	variants := []retentionVariant{
+		{
+			name:   "Batch DeleteMany",
+			create: createRegularCollection,
+			start:  startBatchDelete,
+		},
+		{
+			name:   "TTL index",
+			create: createRegularCollection,
+			start:  startTTLIndex,
+		},
+		{
+			name:       "Time-series expireAfterSeconds",
+			create:     createExpiringTimeSeries,
+			timeSeries: true,
+		},
+	}
+
+	// Let the TTL monitor run every second instead of every minute when allowed to
+	restore, err := speedUpTTLMonitor(ctx)
+	if err != nil {
+		fmt.Printf("Could not shorten the TTL monitor interval, expiry may take up to a minute: %v\n", err)
+	} else {
+		defer restore()
+	}
+
+	events := generateEvents(ctx.Docs, 2*retentionPeriod)
+
+	var results []VariantResult
+	for i, v := range variants {
+		fmt.Printf("Measuring retention: %s\n", v.name)
+
+		coll, err := v.create(ctx, fmt.Sprintf("retention_%d", i))
+		var se mongo.ServerError
+		if v.timeSeries && errors.As(err, &se) {
+			results = append(results, VariantResult{
+				Scenario: "Retention",
+				Variant:  v.name,
+				Outcome:  OutcomeUnsupported,
+				Metrics:  []Metric{{"Reason", err.Error()}},
+			})
+			continue
+		}
+		if err != nil {
+			return results, err
+		}
+		if err := seedEvents(ctx.Ctx, coll, events); err != nil {
+			return results, err
+		}
+
+		cutoff := time.Now().Add(-retentionPeriod)
+		expired := bson.M{"timestamp": bson.M{"$lt": cutoff}}
+		foreground := bson.M{
+			"timestamp":      bson.M{"$gte": time.Now().Add(-time.Hour)},
+			"severity.level": bson.M{"$gte": 3},
+		}
+
+		before, err := getCollectionStats(ctx.Ctx, coll)
+		if err != nil {
+			return results, err
+		}
+		expiredBefore, err := coll.CountDocuments(ctx.Ctx, expired)
+		if err != nil {
+			return results, err
+		}
+		baseline, _, err := countFind(ctx.Ctx, coll, foreground)
+		if err != nil {
+			return results, err
+		}
+
+		// Time-series retention is finished once every whole bucket past the cutoff is gone
+		finished := expired
+		if v.timeSeries {
+			finished = bson.M{"timestamp": bson.M{"$lt": cutoff.Add(-timeSeriesBucketSpan)}}
+		}
+
+		begin := time.Now()
+		var done <-chan error
+		if v.start != nil {
+			if done, err = v.start(ctx, coll, cutoff); err != nil {
+				return results, err
+			}
+		}
+
+		// Keep running the foreground query until retention has caught up
+		var latencies []time.Duration
+		var remaining int64 = expiredBefore
+		lastCheck := time.Now()
+		for time.Since(begin) < retentionTimeout {
+			if done != nil {
+				select {
+				case err := <-done:
+					if err != nil {
+						return results, err
+					}
+					done = nil
+				default:
+				}
+			}
+
+			latency, _, err := countFind(ctx.Ctx, coll, foreground)
+			if err != nil {
+				return results, err
+			}
+			latencies = append(latencies, latency)
+
+			if time.Since(lastCheck) >= retentionCheckInterval {
+				lastCheck = time.Now()
+				if remaining, err = coll.CountDocuments(ctx.Ctx, finished); err != nil {
+					return results, err
+				}
+				if remaining == 0 && done == nil {
+					break
+				}
+			}
+		}
+		elapsed := time.Since(begin)
+
+		// Expired events still kept because their bucket holds newer ones
+		left, err := coll.CountDocuments(ctx.Ctx, expired)
+		if err != nil {
+			return results, err
+		}
+
+		after, err := getCollectionStats(ctx.Ctx, coll)
+		if err != nil {
+			return results, err
+		}
+
+		avg, slowest := latencySummary(latencies)
+		result := VariantResult{
+			Scenario: "Retention",
+			Variant:  v.name,
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Expired events before", expiredBefore},
+				{"Expired events remaining", remaining},
+				{"Retention time", elapsed},
+				{"Foreground baseline latency", baseline},
+				{"Foreground queries during retention", len(latencies)},
+				{"Avg foreground latency", avg},
+				{"Max foreground latency", slowest},
+				{"Storage size before", formatMB(before.StorageSize)},
+				{"Storage size after", formatMB(after.StorageSize)},
+				{"Index size after", formatMB(after.TotalIndexSize)},
+			},
+		}
+		if v.timeSeries {
+			result.Metrics = append(result.Metrics,
+				Metric{"Expired events kept by bucket lag", left - remaining})
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// createRegularCollection creates a plain collection with an index serving the foreground query
+func createRegularCollection(ctx *ScenarioContext, name string) (*mongo.Collection, error) {
+	coll, err := ctx.Collection(name)
+	if err != nil {
+		return nil, err
+	}
+	_, err = coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "timestamp", Value: -1}, {Key: "severity.level", Value: -1}},
+	})
+	return coll, err
+}
+
+// createExpiringTimeSeries creates a time-series collection that expires events on its own
+func createExpiringTimeSeries(ctx *ScenarioContext, name string) (*mongo.Collection, error) {
+	coll, err := ctx.Collection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := options.CreateCollection().
+		SetTimeSeriesOptions(options.TimeSeries().
+			SetTimeField("timestamp").
+			SetMetaField("sourceSystem")).
+		SetExpireAfterSeconds(int64(retentionPeriod.Seconds()))
+	if err := ctx.Database.CreateCollection(ctx.Ctx, coll.Name(), opts); err != nil {
+		return nil, err
+	}
+	return coll, nil
+}
+
+// startBatchDelete removes expired events with a single DeleteMany in the background
+func startBatchDelete(ctx *ScenarioContext, coll *mongo.Collection, cutoff time.Time) (<-chan error, error) {
+	done := make(chan error, 1)
+	go func() {
+		_, err := coll.DeleteMany(ctx.Ctx, bson.M{"timestamp": bson.M{"$lt": cutoff}})
+		done <- err
+	}()
+	return done, nil
+}
+
+// startTTLIndex adds a TTL index on timestamp and leaves expiry to the TTL monitor
+func startTTLIndex(ctx *ScenarioContext, coll *mongo.Collection, cutoff time.Time) (<-chan error, error) {
+	_, err := coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "timestamp", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(retentionPeriod.Seconds())),
+	})
+	return nil, err
+}
+
+// speedUpTTLMonitor sets ttlMonitorSleepSecs to one second and returns a function restoring it
+func speedUpTTLMonitor(ctx *ScenarioContext) (func(), error) {
+	admin := ctx.Client.Database("admin")
+
+	var current struct {
+		Seconds int32 `bson:"ttlMonitorSleepSecs"`
+	}
+	err := admin.RunCommand(ctx.Ctx, bson.D{
+		{Key: "getParameter", Value: 1},
+		{Key: "ttlMonitorSleepSecs", Value: 1},
+	}).Decode(&current)
+	if err != nil {
+		return nil, err
+	}
+
+	err = admin.RunCommand(ctx.Ctx, bson.D{
+		{Key: "setParameter", Value: 1},
+		{Key: "ttlMonitorSleepSecs", Value: 1},
+	}).Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return func() {
+		err := admin.RunCommand(ctx.Ctx, bson.D{
+			{Key: "setParameter", Value: 1},
+			{Key: "ttlMonitorSleepSecs", Value: current.Seconds},
+		}).Err()
+		if err != nil {
+			log.Printf("Failed to restore ttlMonitorSleepSecs to %d: %v", current.Seconds, err)
+		}
+	}, nil
+}
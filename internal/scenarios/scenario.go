@@ -0,0 +1,92 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"mongo-bench/internal/database"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CollectionPrefix is prepended to every collection a scenario creates
+const CollectionPrefix = "scenario_"
+
+// ScenarioContext holds the client and database a scenario works against
+type ScenarioContext struct {
+	Ctx         context.Context
+	Config      database.MongoConfig
+	Client      *mongo.Client
+	Database    *mongo.Database
+	Docs        int
+	Collections int
+
+	collections []string
+}
+
+// ScenarioFunc defines a function type for scenarios
+type ScenarioFunc func(ctx *ScenarioContext) ([]VariantResult, error)
+
+// Scenario holds a scenario name and its function
+type Scenario struct {
+	Name    string
+	RunFunc ScenarioFunc
+}
+
+// GetScenarios returns all scenarios
+func GetScenarios() []Scenario {
+	return []Scenario{
+		{"UnboundedArrays", UnboundedArrays},
+		{"JavaScriptFilters", JavaScriptFilters},
+		{"NegationFilters", NegationFilters},
+		{"IndexOverhead", IndexOverhead},
+		{"GroupPushRoot", GroupPushRoot},
+		{"OrFilters", OrFilters},
+		{"MetadataQueries", MetadataQueries},
+		{"TimestampFormats", TimestampFormats},
+		{"ChangeStreams", ChangeStreams},
+		{"Retention", Retention},
+		{"TimeSeriesLayout", TimeSeriesLayout},
+		{"Transactions", Transactions},
+		{"WriteConcerns", WriteConcerns},
+		{"LargeInLists", LargeInLists},
+		{"UnwindExplosion", UnwindExplosion},
+		{"CoveredQueries", CoveredQueries},
+		{"ConnectionPool", ConnectionPool},
+		{"LookupIndexes", LookupIndexes},
+		{"ConcurrentUpserts", ConcurrentUpserts},
+		{"IndexKeyOrder", IndexKeyOrder},
+		{"TextSearch", TextSearch},
+		{"DenormalizedSeverity", DenormalizedSeverity},
+		{"CollectionExplosion", CollectionExplosion},
+		{"CursorStreaming", CursorStreaming},
+		{"UnboundedQueries", UnboundedQueries},
+	}
+}
+
+// Collection drops and returns a scenario-owned collection so every run starts empty
+func (s *ScenarioContext) Collection(name string) (*mongo.Collection, error) {
+	coll := s.Database.Collection(CollectionPrefix + name)
+	if err := coll.Drop(s.Ctx); err != nil {
+		return nil, fmt.Errorf("failed to drop collection %s: %w", coll.Name(), err)
+	}
+
+	for _, existing := range s.collections {
+		if existing == coll.Name() {
+			return coll, nil
+		}
+	}
+	s.collections = append(s.collections, coll.Name())
+	return coll, nil
+}
+
+// Cleanup drops every collection created through Collection
+func (s *ScenarioContext) Cleanup() {
+	for _, name := range s.collections {
+		if err := s.Database.Collection(name).Drop(s.Ctx); err != nil {
+			log.Printf("Failed to drop collection %s: %v", name, err)
+		}
+	}
+	s.collections = nil
+}
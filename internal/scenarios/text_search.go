@@ -0,0 +1,178 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Shortest prefix stored in the keyword array
+	keywordMinPrefix = 3
+	// Number of top results shown per search
+	textTopResults = 3
+)
+
+// Terms searched by every variant
+var textSearchTerms = []string{"connection", "fail", "procedure"}
+
+// TextSearch compares $regex on description with a $text index and a prefix-tokenized keyword array
+func TextSearch(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Free-text search with unanchored regular expressions")
+
+	coll, err := ctx.Collection("text_search")
+	if err != nil {
+		return nil, err
+	}
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+	docs := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		doc, err := eventDoc(e)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, append(doc, bson.E{
+			Key:   "keywords",
+			Value: keywordPrefixes(e.Description + " " + e.ResolutionNotes),
+		}))
+	}
+	if err := insertBatches(ctx.Ctx, coll, docs); err != nil {
+		return nil, err
+	}
+	if _, err := coll.Indexes().CreateMany(ctx.Ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "description", Value: "text"}, {Key: "resolutionNotes", Value: "text"}}},
+		{Keys: bson.D{{Key: "keywords", Value: 1}}},
+	}); err != nil {
+		return nil, err
+	}
+
+	var results []VariantResult
+	for _, term := range textSearchTerms {
+		variants := []struct {
+			name   string
+			filter bson.M
+			opts   *options.FindOptions
+		}{
+			{
+				name:   "$regex on description",
+				filter: bson.M{"description": bson.M{"$regex": regexp.QuoteMeta(term), "$options": "i"}},
+				opts:   options.Find(),
+			},
+			{
+				name:   "$text sorted by score",
+				filter: bson.M{"$text": bson.M{"$search": term}},
+				opts: options.Find().
+					SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}, "description": 1, "metadata.eventId": 1}).
+					SetSort(bson.M{"score": bson.M{"$meta": "textScore"}}),
+			},
+			{
+				name:   "Keyword prefix array",
+				filter: bson.M{"keywords": strings.ToLower(term)},
+				opts:   options.Find(),
+			},
+		}
+
+		// The regex results are the baseline the other variants are compared with
+		var baseline map[string]bool
+		for _, v := range variants {
+			name := fmt.Sprintf("%q: %s", term, v.name)
+			fmt.Printf("Measuring search: %s\n", name)
+
+			begin := time.Now()
+			ids, top, err := searchEvents(ctx.Ctx, coll, v.filter, v.opts)
+			if err != nil {
+				return results, err
+			}
+			latency := time.Since(begin)
+
+			explain, err := explainFind(ctx.Ctx, coll, v.filter)
+			if err != nil {
+				return results, err
+			}
+
+			if baseline == nil {
+				baseline = ids
+			}
+			shared, extra := 0, 0
+			for id := range ids {
+				if baseline[id] {
+					shared++
+				} else {
+					extra++
+				}
+			}
+
+			results = append(results, VariantResult{
+				Scenario: "TextSearch",
+				Variant:  name,
+				Outcome:  OutcomeOK,
+				Metrics: []Metric{
+					{"Latency", latency},
+					{"Events found", len(ids)},
+					{"Also found by $regex", shared},
+					{"Not found by $regex", extra},
+					{"Missed from $regex", len(baseline) - shared},
+					{"Top results", strings.Join(top, " | ")},
+					{"Keys examined", explain.KeysExamined},
+					{"Docs examined", explain.DocsExamined},
+					{"Plan", explain.PlanShape},
+				},
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// searchEvents runs a search and returns the matching event IDs and the first few descriptions
+func searchEvents(ctx context.Context, coll *mongo.Collection, filter bson.M, opts *options.FindOptions) (map[string]bool, []string, error) {
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := make(map[string]bool)
+	var top []string
+	for cursor.Next(ctx) {
+		var doc struct {
+			Description string `bson:"description"`
+			Metadata    struct {
+				EventID string `bson:"eventId"`
+			} `bson:"metadata"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, nil, err
+		}
+
+		ids[doc.Metadata.EventID] = true
+		if len(top) < textTopResults {
+			top = append(top, doc.Description)
+		}
+	}
+	return ids, top, cursor.Err()
+}
+
+// keywordPrefixes splits text into lowercase words and returns every prefix of at least keywordMinPrefix characters
+func keywordPrefixes(text string) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) {
+		for n := keywordMinPrefix; n <= len(word); n++ {
+			if prefix := word[:n]; !seen[prefix] {
+				seen[prefix] = true
+				keywords = append(keywords, prefix)
+			}
+		}
+	}
+	return keywords
+}
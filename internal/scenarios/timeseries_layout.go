@@ -0,0 +1,125 @@
+package scenarios
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"mongo-bench/internal/database"
+	"mongo-bench/internal/utils"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TimeSeriesLayout runs the query suite against a regular events collection and
+// time-series collections with sourceSystem and eventType as metaField
+func TimeSeriesLayout(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Storing append-only telemetry in a regular collection")
+
+	layouts := []struct {
+		name       string
+		collection string
+		// An empty metaField means a regular collection
+		metaField string
+	}{
+		{"Regular", "layout_regular", ""},
+		{"Time-series by sourceSystem", "layout_ts_source", "sourceSystem"},
+		{"Time-series by eventType", "layout_ts_type", "eventType"},
+	}
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+	pairs := utils.GetQueryTestPairs()
+
+	var results []VariantResult
+	// Per test, one metric per layout
+	testMetrics := make([][]Metric, len(pairs))
+
+	for _, layout := range layouts {
+		fmt.Printf("Measuring layout: %s\n", layout.name)
+
+		coll, err := ctx.Collection(layout.collection)
+		if err != nil {
+			return results, err
+		}
+
+		if layout.metaField != "" {
+			err := database.CreateTimeSeriesCollection(ctx.Ctx, ctx.Database, coll.Name(), layout.metaField)
+			var se mongo.ServerError
+			if errors.As(err, &se) && hasAnyErrorCode(se, timeSeriesUnsupportedCodes) {
+				results = append(results, VariantResult{
+					Scenario: "TimeSeriesLayout",
+					Variant:  layout.name + ": storage",
+					Outcome:  OutcomeUnsupported,
+					Metrics:  []Metric{{"Reason", err.Error()}},
+				})
+				for i := range pairs {
+					testMetrics[i] = append(testMetrics[i], Metric{layout.name, OutcomeUnsupported})
+				}
+				continue
+			}
+			if err != nil {
+				return results, err
+			}
+		}
+
+		begin := time.Now()
+		if err := seedEvents(ctx.Ctx, coll, events); err != nil {
+			return results, err
+		}
+		insertTime := time.Since(begin)
+
+		storage := VariantResult{
+			Scenario: "TimeSeriesLayout",
+			Variant:  layout.name + ": storage",
+			Outcome:  OutcomeOK,
+		}
+		if _, err := database.CreateEventIndexes(ctx.Ctx, coll); err != nil {
+			// Older servers only allow secondary indexes on the time and meta fields
+			storage.Metrics = append(storage.Metrics, Metric{"Event indexes", err.Error()})
+		}
+
+		stats, err := getCollectionStats(ctx.Ctx, coll)
+		if err != nil {
+			return results, err
+		}
+		storage.Metrics = append(storage.Metrics,
+			Metric{"Insert time", insertTime},
+			Metric{"Data size", formatMB(stats.Size)},
+			Metric{"Storage size", formatMB(stats.StorageSize)},
+			Metric{"Total index size", formatMB(stats.TotalIndexSize)},
+		)
+		results = append(results, storage)
+
+		// Run the whole query suite against this layout
+		queryContext := &utils.QueryContext{
+			Ctx:        ctx.Ctx,
+			Collection: coll,
+		}
+		for i, pair := range pairs {
+			profile, err := utils.ProfileFunc(pair.Name, func() error {
+				return pair.TestFunc(queryContext)
+			})
+
+			outcome, ok := classifyError(err)
+			if !ok {
+				return results, err
+			}
+			if outcome != OutcomeOK {
+				testMetrics[i] = append(testMetrics[i], Metric{layout.name, outcome})
+				continue
+			}
+			testMetrics[i] = append(testMetrics[i], Metric{layout.name, profile.ExecutionTime})
+		}
+	}
+
+	for i, pair := range pairs {
+		results = append(results, VariantResult{
+			Scenario: "TimeSeriesLayout",
+			Variant:  pair.Name,
+			Outcome:  OutcomeOK,
+			Metrics:  testMetrics[i],
+		})
+	}
+
+	return results, nil
+}
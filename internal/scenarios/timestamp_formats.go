@@ -0,0 +1,232 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mongo-bench/internal/models"
+	"mongo-bench/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Number of most recent events compared by the sort check
+const timestampSortLimit = 100
+
+// TimestampFormats compares range queries, grouping by day and sorting across
+// BSON Date, ISO string and epoch millisecond timestamps
+func TimestampFormats(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Storing timestamps as strings or epoch numbers")
+
+	events := generateEvents(ctx.Docs, 72*time.Hour)
+	since := time.Now().Add(-24 * time.Hour)
+
+	// The same boundary and day bucket expressed in each representation
+	sinceValues := map[string]interface{}{
+		models.TimestampDate:   since,
+		models.TimestampString: since.UTC().Format(time.RFC3339Nano),
+		models.TimestampInt64:  since.UnixMilli(),
+	}
+	dayKeys := map[string]interface{}{
+		models.TimestampDate: bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$timestamp"}},
+		// Takes the producer's local date, not the UTC date
+		models.TimestampString: bson.M{"$substrBytes": bson.A{"$timestamp", 0, 10}},
+		models.TimestampInt64: bson.M{"$dateToString": bson.M{
+			"format": "%Y-%m-%d",
+			"date":   bson.M{"$toDate": "$timestamp"},
+		}},
+	}
+
+	collections := make(map[string]*mongo.Collection)
+	for _, format := range models.TimestampFormats {
+		coll, err := ctx.Collection("timestamp_" + format)
+		if err != nil {
+			return nil, err
+		}
+
+		docs := make([]interface{}, 0, len(events))
+		for _, e := range events {
+			doc, err := utils.ConvertTimestamp(e, format)
+			if err != nil {
+				return nil, err
+			}
+			docs = append(docs, doc)
+		}
+		if err := insertBatches(ctx.Ctx, coll, docs); err != nil {
+			return nil, err
+		}
+		if _, err := coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+			Keys: bson.D{{Key: "timestamp", Value: -1}},
+		}); err != nil {
+			return nil, err
+		}
+		collections[format] = coll
+	}
+
+	// BSON Date results are the reference the other representations are checked against
+	var (
+		refFound int
+		refDays  map[string]int64
+		refOrder []string
+	)
+
+	var results []VariantResult
+	for _, format := range models.TimestampFormats {
+		fmt.Printf("Measuring timestamp format: %s\n", format)
+		coll := collections[format]
+
+		// Range query over the last 24 hours
+		filter := bson.M{"timestamp": bson.M{"$gte": sinceValues[format]}}
+		rangeLatency, found, err := countFind(ctx.Ctx, coll, filter)
+		if err != nil {
+			return results, err
+		}
+		explain, err := explainFind(ctx.Ctx, coll, filter)
+		if err != nil {
+			return results, err
+		}
+
+		// Group by day
+		begin := time.Now()
+		days, err := countByDay(ctx.Ctx, coll, dayKeys[format])
+		if err != nil {
+			return results, err
+		}
+		groupLatency := time.Since(begin)
+
+		// Sort by timestamp
+		begin = time.Now()
+		order, err := recentEventIDs(ctx.Ctx, coll)
+		if err != nil {
+			return results, err
+		}
+		sortLatency := time.Since(begin)
+
+		if format == models.TimestampDate {
+			refFound, refDays, refOrder = found, days, order
+		}
+
+		results = append(results,
+			VariantResult{
+				Scenario: "TimestampFormats",
+				Variant:  format + ": range query",
+				Outcome:  OutcomeOK,
+				Metrics: append([]Metric{
+					{"Latency", rangeLatency},
+					{"Events found", found},
+					{"Difference vs BSON Date", found - refFound},
+				}, explainMetrics(explain)...),
+			},
+			VariantResult{
+				Scenario: "TimestampFormats",
+				Variant:  format + ": $group by day",
+				Outcome:  OutcomeOK,
+				Metrics: []Metric{
+					{"Latency", groupLatency},
+					{"Days", len(days)},
+					{"Days with wrong counts vs BSON Date", mismatchedDays(refDays, days)},
+				},
+			},
+			VariantResult{
+				Scenario: "TimestampFormats",
+				Variant:  format + ": sort",
+				Outcome:  OutcomeOK,
+				Metrics: []Metric{
+					{"Latency", sortLatency},
+					{"Positions out of order vs BSON Date", outOfOrder(refOrder, order)},
+				},
+			},
+		)
+	}
+
+	return results, nil
+}
+
+// countByDay groups the collection by the given day expression and counts events per day
+func countByDay(ctx context.Context, coll *mongo.Collection, dayKey interface{}) (map[string]int64, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   dayKey,
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Day   string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	days := make(map[string]int64, len(groups))
+	for _, g := range groups {
+		days[g.Day] = g.Count
+	}
+	return days, nil
+}
+
+// recentEventIDs returns the event IDs of the most recent events in timestamp sort order
+func recentEventIDs(ctx context.Context, coll *mongo.Collection) ([]string, error) {
+	opts := options.Find().
+		SetSort(bson.M{"timestamp": -1}).
+		SetLimit(timestampSortLimit).
+		SetProjection(bson.M{"metadata.eventId": 1})
+
+	cursor, err := coll.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		Metadata struct {
+			EventID string `bson:"eventId"`
+		} `bson:"metadata"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.Metadata.EventID)
+	}
+	return ids, nil
+}
+
+// mismatchedDays counts the days whose event count differs from the reference
+func mismatchedDays(ref, days map[string]int64) int {
+	n := 0
+	for day, count := range ref {
+		if days[day] != count {
+			n++
+		}
+	}
+	for day := range days {
+		if _, ok := ref[day]; !ok {
+			n++
+		}
+	}
+	return n
+}
+
+// outOfOrder counts the positions where the order differs from the reference
+func outOfOrder(ref, order []string) int {
+	n := 0
+	for i := range ref {
+		if i >= len(order) || order[i] != ref[i] {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,218 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Number of concurrent writers per variant
+	txnWorkers = 4
+	// Number of events per batched write
+	txnBatchSize = 100
+)
+
+// txnVariant describes one way of writing a batch of events
+type txnVariant struct {
+	name          string
+	batchSize     int
+	transactional bool
+	// write stores the batch and returns how many times a transaction callback ran
+	write func(ctx context.Context, client *mongo.Client, coll *mongo.Collection, batch []models.Event) (int, error)
+}
+
+// Transactions compares single inserts and bulk writes with and without multi-document transactions
+func Transactions(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Wrapping every insert in a multi-document transaction")
+
+	variants := []txnVariant{
+		{"InsertOne", 1, false, insertOneWrite},
+		{"InsertOne in WithTransaction", 1, true, insertOneInTransaction},
+		{"InsertMany in WithTransaction", txnBatchSize, true, insertManyInTransaction},
+		{"Unordered BulkWrite", txnBatchSize, false, bulkWrite},
+	}
+
+	names := make([]string, 0, len(variants))
+	for _, v := range variants {
+		names = append(names, v.name)
+	}
+	ok, err := isReplicaSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return unsupportedResults("Transactions", names, "transactions require a replica set"), nil
+	}
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+
+	var results []VariantResult
+	for _, v := range variants {
+		fmt.Printf("Measuring writes: %s\n", v.name)
+
+		coll, err := ctx.Collection("transactions")
+		if err != nil {
+			return results, err
+		}
+		// Transactions cannot create collections on older servers, so create it up front
+		if err := ctx.Database.CreateCollection(ctx.Ctx, coll.Name()); err != nil {
+			return results, err
+		}
+
+		var (
+			mu        sync.Mutex
+			latencies []time.Duration
+			firstErr  error
+			retries   int64
+			aborted   int64
+			failed    int64
+			wg        sync.WaitGroup
+		)
+
+		// Split the events into batches and hand them out to the workers
+		batches := make(chan []models.Event)
+		begin := time.Now()
+		for w := 0; w < txnWorkers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for batch := range batches {
+					start := time.Now()
+					runs, err := v.write(ctx.Ctx, ctx.Client, coll, batch)
+					elapsed := time.Since(start)
+
+					// Every callback run that did not end in a commit was aborted,
+					// including the last one when the transaction failed for good
+					if runs > 0 {
+						atomic.AddInt64(&retries, int64(runs-1))
+						if err == nil {
+							runs--
+						}
+						atomic.AddInt64(&aborted, int64(runs))
+					}
+					mu.Lock()
+					latencies = append(latencies, elapsed)
+					if err != nil {
+						failed++
+						if firstErr == nil {
+							firstErr = err
+						}
+					}
+					mu.Unlock()
+				}
+			}()
+		}
+		for start := 0; start < len(events); start += v.batchSize {
+			end := start + v.batchSize
+			if end > len(events) {
+				end = len(events)
+			}
+			batches <- events[start:end]
+		}
+		close(batches)
+		wg.Wait()
+		elapsed := time.Since(begin)
+
+		outcome, ok := classifyError(firstErr)
+		if !ok {
+			return results, firstErr
+		}
+
+		stored, err := coll.CountDocuments(ctx.Ctx, bson.M{})
+		if err != nil {
+			return results, err
+		}
+
+		avg, slowest := latencySummary(latencies)
+		result := VariantResult{
+			Scenario: "Transactions",
+			Variant:  v.name,
+			Outcome:  outcome,
+			Metrics: []Metric{
+				{"Total time", elapsed},
+				{"Throughput (docs/s)", float64(stored) / elapsed.Seconds()},
+				{"Operations", len(latencies)},
+				{"Avg operation latency", avg},
+				{"Max operation latency", slowest},
+				{"Documents stored", stored},
+				{"Failed operations", failed},
+			},
+		}
+		// Only the transactional variants run callbacks
+		if v.transactional {
+			result.Metrics = append(result.Metrics,
+				Metric{"Transactions aborted", aborted},
+				Metric{"Callback retries", retries},
+			)
+		}
+		if firstErr != nil {
+			result.Metrics = append(result.Metrics, Metric{"First error", firstErr.Error()})
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// insertOneWrite inserts a single event without a transaction
+func insertOneWrite(ctx context.Context, _ *mongo.Client, coll *mongo.Collection, batch []models.Event) (int, error) {
+	_, err := coll.InsertOne(ctx, batch[0])
+	return 0, err
+}
+
+// insertOneInTransaction inserts a single event inside its own transaction
+func insertOneInTransaction(ctx context.Context, client *mongo.Client, coll *mongo.Collection, batch []models.Event) (int, error) {
+	return withTransaction(ctx, client, func(sc mongo.SessionContext) error {
+		_, err := coll.InsertOne(sc, batch[0])
+		return err
+	})
+}
+
+// insertManyInTransaction inserts a batch of events inside one transaction
+func insertManyInTransaction(ctx context.Context, client *mongo.Client, coll *mongo.Collection, batch []models.Event) (int, error) {
+	docs := make([]interface{}, 0, len(batch))
+	for _, e := range batch {
+		docs = append(docs, e)
+	}
+
+	return withTransaction(ctx, client, func(sc mongo.SessionContext) error {
+		_, err := coll.InsertMany(sc, docs)
+		return err
+	})
+}
+
+// bulkWrite inserts a batch of events with an unordered bulk write and no transaction
+func bulkWrite(ctx context.Context, _ *mongo.Client, coll *mongo.Collection, batch []models.Event) (int, error) {
+	writes := make([]mongo.WriteModel, 0, len(batch))
+	for _, e := range batch {
+		writes = append(writes, mongo.NewInsertOneModel().SetDocument(e))
+	}
+
+	_, err := coll.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return 0, err
+}
+
+// withTransaction runs fn in a new session's transaction and returns how often the driver invoked it
+func withTransaction(ctx context.Context, client *mongo.Client, fn func(mongo.SessionContext) error) (int, error) {
+	session, err := client.StartSession()
+	if err != nil {
+		return 0, err
+	}
+	defer session.EndSession(ctx)
+
+	runs := 0
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		runs++
+		return nil, fn(sc)
+	})
+	return runs, err
+}
@@ -0,0 +1,280 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mongo-bench/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Number of events a single hourly bucket may hold before a new one is started
+	arrayBucketSize = 200
+	// Number of volume steps measured per layout
+	arrayVolumeSteps = 4
+)
+
+// arrayLayout describes one way of storing events per source system
+type arrayLayout struct {
+	name       string
+	collection string
+	indexes    []mongo.IndexModel
+	write      func(ctx context.Context, coll *mongo.Collection, e models.Event) error
+	read       func(ctx context.Context, coll *mongo.Collection, source string, since time.Time) (int, error)
+}
+
+// UnboundedArrays compares pushing every event into one document per source system
+// with fixed-size hourly buckets and with one document per event
+func UnboundedArrays(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Pushing every event into one ever-growing document per source system")
+
+	layouts := []arrayLayout{
+		{
+			name:       "Unbounded array",
+			collection: "unbounded_array",
+			write:      pushUnbounded,
+			read:       readUnbounded,
+		},
+		{
+			name:       "Hourly buckets",
+			collection: "hourly_buckets",
+			indexes: []mongo.IndexModel{
+				{Keys: bson.D{{Key: "sourceSystem", Value: 1}, {Key: "hour", Value: -1}}},
+			},
+			write: pushBucket,
+			read:  readBuckets,
+		},
+		{
+			name:       "Separate documents",
+			collection: "separate_documents",
+			indexes: []mongo.IndexModel{
+				{Keys: bson.D{{Key: "sourceSystem", Value: 1}, {Key: "timestamp", Value: -1}}},
+			},
+			write: insertSeparate,
+			read:  readSeparate,
+		},
+	}
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+	stepSize := (len(events) + arrayVolumeSteps - 1) / arrayVolumeSteps
+
+	var results []VariantResult
+	for _, layout := range layouts {
+		fmt.Printf("Measuring layout: %s\n", layout.name)
+
+		coll, err := ctx.Collection(layout.collection)
+		if err != nil {
+			return results, err
+		}
+		if len(layout.indexes) > 0 {
+			if _, err := coll.Indexes().CreateMany(ctx.Ctx, layout.indexes); err != nil {
+				return results, err
+			}
+		}
+
+		written := 0
+		for start := 0; start < len(events); start += stepSize {
+			end := start + stepSize
+			if end > len(events) {
+				end = len(events)
+			}
+
+			// Write this step one event at a time, as a producer would
+			var writeTime, maxWrite time.Duration
+			var writeErr error
+			for _, e := range events[start:end] {
+				begin := time.Now()
+				if writeErr = layout.write(ctx.Ctx, coll, e); writeErr != nil {
+					break
+				}
+				elapsed := time.Since(begin)
+				writeTime += elapsed
+				if elapsed > maxWrite {
+					maxWrite = elapsed
+				}
+				written++
+			}
+
+			result := VariantResult{
+				Scenario: "UnboundedArrays",
+				Variant:  fmt.Sprintf("%s @ %d events", layout.name, written),
+				Outcome:  OutcomeOK,
+			}
+
+			if n := end - start; writeErr == nil && n > 0 {
+				result.Metrics = append(result.Metrics,
+					Metric{"Avg write latency", writeTime / time.Duration(n)},
+					Metric{"Max write latency", maxWrite},
+				)
+			}
+
+			docCount, avgSize, maxSize, err := documentSizes(ctx.Ctx, coll)
+			if err != nil {
+				return results, err
+			}
+			result.Metrics = append(result.Metrics,
+				Metric{"Documents", docCount},
+				Metric{"Avg document size", formatMB(int64(avgSize))},
+				Metric{"Max document size", formatMB(maxSize)},
+			)
+
+			readTime, found, err := readAllSources(ctx.Ctx, coll, layout.read)
+			if err != nil {
+				return results, err
+			}
+			result.Metrics = append(result.Metrics,
+				Metric{"Avg read latency (last hour per source)", readTime},
+				Metric{"Events read", found},
+			)
+
+			if writeErr != nil {
+				outcome, ok := classifyError(writeErr)
+				if !ok {
+					return results, writeErr
+				}
+				result.Outcome = outcome
+				result.Metrics = append(result.Metrics, Metric{"Write stopped", writeErr.Error()})
+				results = append(results, result)
+				break
+			}
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// pushUnbounded appends the event to the single document of its source system
+func pushUnbounded(ctx context.Context, coll *mongo.Collection, e models.Event) error {
+	_, err := coll.UpdateOne(ctx,
+		bson.M{"_id": e.SourceSystem},
+		bson.M{"$push": bson.M{"events": e}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// pushBucket appends the event to the open bucket of its source system and hour
+func pushBucket(ctx context.Context, coll *mongo.Collection, e models.Event) error {
+	_, err := coll.UpdateOne(ctx,
+		bson.M{
+			"sourceSystem": e.SourceSystem,
+			"hour":         e.Timestamp.Truncate(time.Hour),
+			"count":        bson.M{"$lt": arrayBucketSize},
+		},
+		bson.M{
+			"$push": bson.M{"events": e},
+			"$inc":  bson.M{"count": 1},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// insertSeparate stores the event as its own document
+func insertSeparate(ctx context.Context, coll *mongo.Collection, e models.Event) error {
+	_, err := coll.InsertOne(ctx, e)
+	return err
+}
+
+// readUnbounded loads the whole source document and filters its events client-side
+func readUnbounded(ctx context.Context, coll *mongo.Collection, source string, since time.Time) (int, error) {
+	var doc struct {
+		Events []models.Event `bson:"events"`
+	}
+	err := coll.FindOne(ctx, bson.M{"_id": source}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return countSince(doc.Events, since), nil
+}
+
+// readBuckets loads only the buckets covering the requested hours
+func readBuckets(ctx context.Context, coll *mongo.Collection, source string, since time.Time) (int, error) {
+	filter := bson.M{
+		"sourceSystem": source,
+		"hour":         bson.M{"$gte": since.Truncate(time.Hour)},
+	}
+
+	cursor, err := coll.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var buckets []struct {
+		Events []models.Event `bson:"events"`
+	}
+	if err = cursor.All(ctx, &buckets); err != nil {
+		return 0, err
+	}
+
+	found := 0
+	for _, b := range buckets {
+		found += countSince(b.Events, since)
+	}
+	return found, nil
+}
+
+// readSeparate queries the matching event documents directly
+func readSeparate(ctx context.Context, coll *mongo.Collection, source string, since time.Time) (int, error) {
+	filter := bson.M{
+		"sourceSystem": source,
+		"timestamp":    bson.M{"$gte": since},
+	}
+
+	cursor, err := coll.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.Event
+	if err = cursor.All(ctx, &events); err != nil {
+		return 0, err
+	}
+	return len(events), nil
+}
+
+// readAllSources runs the read of every source system and returns the average latency
+func readAllSources(
+	ctx context.Context,
+	coll *mongo.Collection,
+	read func(context.Context, *mongo.Collection, string, time.Time) (int, error),
+) (time.Duration, int, error) {
+	since := time.Now().Add(-time.Hour)
+
+	var total time.Duration
+	found := 0
+	for _, source := range models.SourceSystems {
+		begin := time.Now()
+		n, err := read(ctx, coll, source, since)
+		if err != nil {
+			return 0, 0, err
+		}
+		total += time.Since(begin)
+		found += n
+	}
+
+	return total / time.Duration(len(models.SourceSystems)), found, nil
+}
+
+// countSince counts events at or after the given time
+func countSince(events []models.Event, since time.Time) int {
+	n := 0
+	for _, e := range events {
+		if !e.Timestamp.Before(since) {
+			n++
+		}
+	}
+	return n
+}
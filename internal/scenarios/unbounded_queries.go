@@ -0,0 +1,179 @@
+package scenarios
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// Time limit applied by the bounded variants
+	unboundedTimeLimit = 500 * time.Millisecond
+	// Number of documents the limited runaway query starts from
+	unboundedLimit = 100
+	// How long foreground queries run on their own for the baseline
+	unboundedBaseline = 2 * time.Second
+)
+
+// UnboundedQueries runs a runaway self-join without limit or time limit, with maxTimeMS,
+// with a context deadline and with a $limit, while foreground queries run alongside it
+func UnboundedQueries(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Queries without a limit or a time limit")
+
+	coll, err := ctx.Collection("unbounded_queries")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, coll, generateEvents(ctx.Docs, 24*time.Hour)); err != nil {
+		return nil, err
+	}
+	if _, err := coll.Indexes().CreateOne(ctx.Ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "sourceSystem", Value: 1}},
+	}); err != nil {
+		return nil, err
+	}
+
+	// Every event counts the events sharing its description with an unindexed sub-pipeline,
+	// so the work grows with the square of the collection size
+	runaway := mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from": coll.Name(),
+			"let":  bson.M{"description": "$description"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$description", "$$description"}}}},
+				bson.M{"$count": "n"},
+			},
+			"as": "related",
+		}}},
+	}
+	limited := append(mongo.Pipeline{{{Key: "$limit", Value: unboundedLimit}}}, runaway...)
+
+	variants := []struct {
+		name     string
+		pipeline mongo.Pipeline
+		opts     *options.AggregateOptions
+		deadline time.Duration
+	}{
+		{"Unbounded", runaway, options.Aggregate(), 0},
+		{fmt.Sprintf("maxTimeMS %v", unboundedTimeLimit), runaway, options.Aggregate().SetMaxTime(unboundedTimeLimit), 0},
+		{fmt.Sprintf("Context deadline %v", unboundedTimeLimit), runaway, options.Aggregate(), unboundedTimeLimit},
+		{fmt.Sprintf("$limit %d with maxTimeMS %v", unboundedLimit, unboundedTimeLimit), limited, options.Aggregate().SetMaxTime(unboundedTimeLimit), 0},
+	}
+
+	var results []VariantResult
+
+	// Foreground queries on their own first, to have something to compare with
+	fmt.Println("Measuring foreground queries without a runaway query")
+	latencies, err := foregroundQueries(ctx.Ctx, coll, func() { time.Sleep(unboundedBaseline) })
+	if err != nil {
+		return results, err
+	}
+	avg, slowest := latencySummary(latencies)
+	results = append(results, VariantResult{
+		Scenario: "UnboundedQueries",
+		Variant:  "Foreground queries only",
+		Outcome:  OutcomeOK,
+		Metrics: []Metric{
+			{"Foreground queries", len(latencies)},
+			{"Avg foreground latency", avg},
+			{"Max foreground latency", slowest},
+		},
+	})
+
+	for _, v := range variants {
+		fmt.Printf("Measuring runaway query: %s\n", v.name)
+
+		var (
+			queryErr error
+			elapsed  time.Duration
+			returned int
+		)
+		latencies, err := foregroundQueries(ctx.Ctx, coll, func() {
+			qctx, cancel := ctx.Ctx, context.CancelFunc(func() {})
+			if v.deadline > 0 {
+				qctx, cancel = context.WithTimeout(ctx.Ctx, v.deadline)
+			}
+			defer cancel()
+
+			begin := time.Now()
+			cursor, err := coll.Aggregate(qctx, v.pipeline, v.opts)
+			if err == nil {
+				for cursor.Next(qctx) {
+					returned++
+				}
+				err = cursor.Err()
+				cursor.Close(ctx.Ctx)
+			}
+			elapsed = time.Since(begin)
+			queryErr = err
+		})
+		if err != nil {
+			return results, err
+		}
+
+		outcome, ok := classifyError(queryErr)
+		if !ok {
+			return results, queryErr
+		}
+
+		avg, slowest := latencySummary(latencies)
+		result := VariantResult{
+			Scenario: "UnboundedQueries",
+			Variant:  v.name,
+			Outcome:  outcome,
+			Metrics: []Metric{
+				{"Runaway query time", elapsed},
+				{"Documents returned", returned},
+				{"Foreground queries", len(latencies)},
+				{"Avg foreground latency", avg},
+				{"Max foreground latency", slowest},
+			},
+		}
+		if queryErr != nil {
+			result.Metrics = append(result.Metrics, Metric{"Error", queryErr.Error()})
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// foregroundQueries keeps running small indexed queries until run returns and
+// returns the latency of each of them
+func foregroundQueries(ctx context.Context, coll *mongo.Collection, run func()) ([]time.Duration, error) {
+	var (
+		latencies []time.Duration
+		queryErr  error
+		wg        sync.WaitGroup
+	)
+	done := make(chan struct{})
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			begin := time.Now()
+			if err := findOneBySource(ctx, coll); err != nil {
+				queryErr = err
+				return
+			}
+			latencies = append(latencies, time.Since(begin))
+		}
+	}()
+
+	run()
+	close(done)
+	wg.Wait()
+	return latencies, queryErr
+}
@@ -0,0 +1,155 @@
+package scenarios
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UnwindExplosion compares unwinding arrays before filtering with filtering first
+// and with array operators that never unwind
+func UnwindExplosion(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: $unwind before $match multiplies the documents every stage sees")
+
+	coll, err := ctx.Collection("unwind")
+	if err != nil {
+		return nil, err
+	}
+	if err := seedEvents(ctx.Ctx, coll, generateEvents(ctx.Docs, 24*time.Hour)); err != nil {
+		return nil, err
+	}
+	if _, err := coll.Indexes().CreateMany(ctx.Ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "affectedComponents", Value: 1}}},
+		{Keys: bson.D{{Key: "tags", Value: 1}}},
+	}); err != nil {
+		return nil, err
+	}
+
+	// Count how often a value occurs per event type
+	arrays := []struct {
+		field string
+		value string
+	}{
+		{"affectedComponents", "Payment Processing"},
+		{"tags", "Security"},
+	}
+
+	var results []VariantResult
+	for _, array := range arrays {
+		path := "$" + array.field
+		groupByType := bson.D{{Key: "$group", Value: bson.M{"_id": "$eventType", "count": bson.M{"$sum": 1}}}}
+
+		variants := []struct {
+			name     string
+			pipeline mongo.Pipeline
+		}{
+			{
+				name: "$unwind then $match",
+				pipeline: mongo.Pipeline{
+					{{Key: "$unwind", Value: path}},
+					{{Key: "$match", Value: bson.M{array.field: array.value}}},
+					groupByType,
+				},
+			},
+			{
+				name: "Indexed $match then $unwind",
+				pipeline: mongo.Pipeline{
+					{{Key: "$match", Value: bson.M{array.field: array.value}}},
+					{{Key: "$unwind", Value: path}},
+					{{Key: "$match", Value: bson.M{array.field: array.value}}},
+					groupByType,
+				},
+			},
+			{
+				name: "Indexed $match with $filter/$size",
+				pipeline: mongo.Pipeline{
+					{{Key: "$match", Value: bson.M{array.field: array.value}}},
+					{{Key: "$group", Value: bson.M{
+						"_id": "$eventType",
+						"count": bson.M{"$sum": bson.M{"$size": bson.M{"$filter": bson.M{
+							"input": path,
+							"cond":  bson.M{"$eq": bson.A{"$$this", array.value}},
+						}}}},
+					}}},
+				},
+			},
+		}
+
+		for _, v := range variants {
+			name := fmt.Sprintf("%s = %q: %s", array.field, array.value, v.name)
+			fmt.Printf("Measuring pipeline: %s\n", name)
+
+			begin := time.Now()
+			groups, err := countAggregate(ctx, coll, v.pipeline)
+			if err != nil {
+				return results, err
+			}
+			latency := time.Since(begin)
+
+			result := VariantResult{
+				Scenario: "UnwindExplosion",
+				Variant:  name,
+				Outcome:  OutcomeOK,
+				Metrics: []Metric{
+					{"Latency", latency},
+					{"Result groups", groups},
+				},
+			}
+
+			// Count the documents leaving each stage by re-running every prefix of the pipeline
+			for i := range v.pipeline {
+				prefix := append(mongo.Pipeline{}, v.pipeline[:i+1]...)
+				prefix = append(prefix, bson.D{{Key: "$count", Value: "n"}})
+
+				var counts []struct {
+					N int64 `bson:"n"`
+				}
+				cursor, err := coll.Aggregate(ctx.Ctx, prefix)
+				if err != nil {
+					return results, err
+				}
+				if err = cursor.All(ctx.Ctx, &counts); err != nil {
+					return results, err
+				}
+
+				var n int64
+				if len(counts) > 0 {
+					n = counts[0].N
+				}
+				result.Metrics = append(result.Metrics,
+					Metric{fmt.Sprintf("Docs after stage %d (%s)", i+1, stageName(v.pipeline[i])), n})
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// countAggregate runs the pipeline and returns the number of result documents
+func countAggregate(ctx *ScenarioContext, coll *mongo.Collection, pipeline mongo.Pipeline) (int, error) {
+	cursor, err := coll.Aggregate(ctx.Ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx.Ctx)
+
+	n := 0
+	for cursor.Next(ctx.Ctx) {
+		n++
+	}
+	return n, cursor.Err()
+}
+
+// stageName returns the operator of a pipeline stage, e.g. $unwind
+func stageName(stage bson.D) string {
+	names := make([]string, 0, len(stage))
+	for _, e := range stage {
+		names = append(names, e.Key)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,110 @@
+package scenarios
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// How long to wait before counting what an unacknowledged writer really stored
+const writeConcernSettle = time.Second
+
+// WriteConcerns inserts the same events with w:0, w:1, w:1 j:true and w:majority
+func WriteConcerns(ctx *ScenarioContext) ([]VariantResult, error) {
+	fmt.Println("Running anti-pattern: Benchmarking writes nobody waits for")
+
+	journal := true
+	variants := []struct {
+		name         string
+		writeConcern *writeconcern.WriteConcern
+		replicaSet   bool
+	}{
+		{"w:0", writeconcern.Unacknowledged(), false},
+		{"w:1", writeconcern.W1(), false},
+		{"w:1, j:true", &writeconcern.WriteConcern{W: 1, Journal: &journal}, false},
+		{"w:majority", writeconcern.Majority(), true},
+	}
+
+	replicaSet, err := isReplicaSet(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := generateEvents(ctx.Docs, 24*time.Hour)
+
+	var results []VariantResult
+	for _, v := range variants {
+		if v.replicaSet && !replicaSet {
+			results = append(results, unsupportedResults("WriteConcerns", []string{v.name},
+				"w:majority needs a replica set to mean more than w:1")...)
+			continue
+		}
+
+		fmt.Printf("Measuring write concern: %s\n", v.name)
+
+		base, err := ctx.Collection("write_concerns")
+		if err != nil {
+			return results, err
+		}
+		coll, err := base.Clone(options.Collection().SetWriteConcern(v.writeConcern))
+		if err != nil {
+			return results, err
+		}
+
+		var latencies []time.Duration
+		acknowledged, unacknowledged, failed := 0, 0, 0
+		begin := time.Now()
+		for _, e := range events {
+			start := time.Now()
+			_, err := coll.InsertOne(ctx.Ctx, e)
+			latencies = append(latencies, time.Since(start))
+
+			switch {
+			case err == nil:
+				acknowledged++
+			case errors.Is(err, mongo.ErrUnacknowledgedWrite):
+				unacknowledged++
+			default:
+				failed++
+			}
+		}
+		elapsed := time.Since(begin)
+
+		// Count through the default collection so the read does not wait behind our own write concern
+		storedAtFinish, err := base.CountDocuments(ctx.Ctx, bson.M{})
+		if err != nil {
+			return results, err
+		}
+		time.Sleep(writeConcernSettle)
+		storedAfterSettle, err := base.CountDocuments(ctx.Ctx, bson.M{})
+		if err != nil {
+			return results, err
+		}
+
+		avg, slowest := latencySummary(latencies)
+		results = append(results, VariantResult{
+			Scenario: "WriteConcerns",
+			Variant:  v.name,
+			Outcome:  OutcomeOK,
+			Metrics: []Metric{
+				{"Writes sent", len(events)},
+				{"Reported throughput (docs/s)", float64(len(events)) / elapsed.Seconds()},
+				{"Persisted throughput (docs/s)", float64(storedAtFinish) / elapsed.Seconds()},
+				{"Avg write latency", avg},
+				{"Max write latency", slowest},
+				{"Acknowledged", acknowledged},
+				{"Unacknowledged", unacknowledged},
+				{"Failed", failed},
+				{"Stored when the writer finished", storedAtFinish},
+				{fmt.Sprintf("Stored %v later", writeConcernSettle), storedAfterSettle},
+			},
+		})
+	}
+
+	return results, nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"mongo-bench/internal/models"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -104,6 +105,45 @@ func GenerateRandomEvent() models.Event {
 	return event
 }
 
+// Offsets string timestamps are written with, as producers in different regions would
+var producerZones = []*time.Location{
+	time.UTC,
+	time.FixedZone("UTC+8", 8*60*60),
+	time.FixedZone("UTC-5", -5*60*60),
+}
+
+// ConvertTimestamp returns the event as a document with its timestamp stored in the given format
+func ConvertTimestamp(e models.Event, format string) (interface{}, error) {
+	var timestamp interface{}
+	switch format {
+	case models.TimestampDate:
+		return e, nil
+	case models.TimestampString:
+		zone := producerZones[rand.Intn(len(producerZones))]
+		timestamp = e.Timestamp.In(zone).Format(time.RFC3339Nano)
+	case models.TimestampInt64:
+		timestamp = e.Timestamp.UnixMilli()
+	default:
+		return nil, fmt.Errorf("unknown timestamp format %q", format)
+	}
+
+	raw, err := bson.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	for i := range doc {
+		if doc[i].Key == "timestamp" {
+			doc[i].Value = timestamp
+		}
+	}
+	return doc, nil
+}
+
 // InsertEvent inserts a single event into MongoDB
 func InsertEvent(ctx context.Context, collection *mongo.Collection, event models.Event, wg *sync.WaitGroup) {
 	defer wg.Done()
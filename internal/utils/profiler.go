@@ -11,14 +11,20 @@ type ProfileResult struct {
 	Name          string
 	ExecutionTime time.Duration
 	MemoryUsage   uint64
+	// TimedOut is set when the function was stopped by a time limit before it finished
+	TimedOut bool
 }
 
 // String returns a formatted string representation of ProfileResult
 func (r ProfileResult) String() string {
-	return fmt.Sprintf("Profile [%s]:\n- Execution time: %v\n- Memory usage: %.2f MB",
+	s := fmt.Sprintf("Profile [%s]:\n- Execution time: %v\n- Memory usage: %.2f MB",
 		r.Name,
 		r.ExecutionTime,
 		float64(r.MemoryUsage)/(1024*1024))
+	if r.TimedOut {
+		s += "\n- Outcome: timed out"
+	}
+	return s
 }
 
 // TimerFunc is the type of the function used to measure execution time and memory usage
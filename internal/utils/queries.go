@@ -2,6 +2,7 @@ package utils
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,6 +17,43 @@ import (
 type QueryContext struct {
 	Ctx        context.Context
 	Collection *mongo.Collection
+	// MaxTime is sent as maxTimeMS with every query when greater than zero
+	MaxTime time.Duration
+}
+
+// FindOptions returns find options carrying the server-side time limit
+func (ctx *QueryContext) FindOptions() *options.FindOptions {
+	opts := options.Find()
+	if ctx.MaxTime > 0 {
+		opts.SetMaxTime(ctx.MaxTime)
+	}
+	return opts
+}
+
+// AggregateOptions returns aggregate options carrying the server-side time limit
+func (ctx *QueryContext) AggregateOptions() *options.AggregateOptions {
+	opts := options.Aggregate()
+	if ctx.MaxTime > 0 {
+		opts.SetMaxTime(ctx.MaxTime)
+	}
+	return opts
+}
+
+// IsTimedOut reports whether a query was stopped by maxTimeMS, a context deadline or killOp.
+// Server selection and network timeouts are failures, not timed-out queries.
+func IsTimedOut(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var ce mongo.CommandError
+	if errors.As(err, &ce) && ce.IsMaxTimeMSExpiredError() {
+		return true
+	}
+
+	// Interrupted, raised when a query is killed with killOp
+	var se mongo.ServerError
+	return errors.As(err, &se) && se.HasErrorCode(11601)
 }
 
 // QueryTestFunc defines a function type for query tests
@@ -59,7 +97,7 @@ func FindAllFieldsAntiPattern(ctx *QueryContext) error {
 		},
 	}
 
-	cursor, err := ctx.Collection.Find(ctx.Ctx, filter)
+	cursor, err := ctx.Collection.Find(ctx.Ctx, filter, ctx.FindOptions())
 	if err != nil {
 		return err
 	}
@@ -94,7 +132,7 @@ func FindWithProjectionOptimized(ctx *QueryContext) error {
 		"_id":          0,
 	}
 
-	opts := options.Find().SetProjection(projection)
+	opts := ctx.FindOptions().SetProjection(projection)
 	cursor, err := ctx.Collection.Find(ctx.Ctx, filter, opts)
 	if err != nil {
 		return err
@@ -127,7 +165,7 @@ func AggregateBeforeFilterAntiPattern(ctx *QueryContext) error {
 		}}},
 	}
 
-	cursor, err := ctx.Collection.Aggregate(ctx.Ctx, pipeline)
+	cursor, err := ctx.Collection.Aggregate(ctx.Ctx, pipeline, ctx.AggregateOptions())
 	if err != nil {
 		return err
 	}
@@ -157,7 +195,7 @@ func FilterBeforeAggregateOptimized(ctx *QueryContext) error {
 		}}},
 	}
 
-	cursor, err := ctx.Collection.Aggregate(ctx.Ctx, pipeline)
+	cursor, err := ctx.Collection.Aggregate(ctx.Ctx, pipeline, ctx.AggregateOptions())
 	if err != nil {
 		return err
 	}
@@ -179,7 +217,7 @@ func FindRecentEvents(ctx *QueryContext) error {
 	fmt.Println("Finding most recent events")
 
 	// Find most recent events
-	opts := options.Find().
+	opts := ctx.FindOptions().
 		SetSort(bson.M{"timestamp": -1}).
 		SetLimit(10)
 
@@ -214,7 +252,7 @@ func FindHighSeverityEvents(ctx *QueryContext) error {
 		"severity.level": bson.M{"$gte": 3},
 	}
 
-	opts := options.Find().
+	opts := ctx.FindOptions().
 		SetSort(bson.M{"timestamp": -1}).
 		SetLimit(10)
 
@@ -254,7 +292,7 @@ func AggregateEventsBySeverity(ctx *QueryContext) error {
 		{{"$sort", bson.M{"_id": 1}}},
 	}
 
-	cursor, err := ctx.Collection.Aggregate(ctx.Ctx, pipeline)
+	cursor, err := ctx.Collection.Aggregate(ctx.Ctx, pipeline, ctx.AggregateOptions())
 	if err != nil {
 		return err
 	}
@@ -294,7 +332,7 @@ func FindEventsWithProjection(ctx *QueryContext) error {
 		"_id":          0,
 	}
 
-	opts := options.Find().
+	opts := ctx.FindOptions().
 		SetProjection(projection).
 		SetLimit(5)
 
@@ -336,7 +374,7 @@ func FindEventsByTimeRange(ctx *QueryContext) error {
 		},
 	}
 
-	opts := options.Find().
+	opts := ctx.FindOptions().
 		SetSort(bson.M{"timestamp": -1}).
 		SetLimit(10)
 
@@ -381,7 +419,7 @@ func ComplexAggregation(ctx *QueryContext) error {
 		{{"$limit", 5}},
 	}
 
-	cursor, err := ctx.Collection.Aggregate(ctx.Ctx, pipeline)
+	cursor, err := ctx.Collection.Aggregate(ctx.Ctx, pipeline, ctx.AggregateOptions())
 	if err != nil {
 		return err
 	}
@@ -418,7 +456,7 @@ func FindEventsWithSorting(ctx *QueryContext) error {
 		}},
 	}
 
-	opts := options.Find().
+	opts := ctx.FindOptions().
 		SetSort(bson.M{"severity.level": -1, "timestamp": -1}).
 		SetLimit(10)
 
@@ -6,6 +6,7 @@ import (
 
 	"mongo-bench/cmd/generate"
 	"mongo-bench/cmd/run"
+	"mongo-bench/cmd/scenario"
 
 	"github.com/spf13/cobra"
 )
@@ -15,14 +16,16 @@ func main() {
 		Use:   "mongo-bench",
 		Short: "MongoDB Benchmark Tool",
 		Long: `MongoDB Benchmark Tool is a tool for generating simulated event data and testing MongoDB query performance.
-The tool provides two main functions:
+The tool provides three main functions:
 1. Generate random event data and write to MongoDB
-2. Execute a series of query benchmark tests and analyze performance`,
+2. Execute a series of query benchmark tests and analyze performance
+3. Execute anti-pattern scenarios that compare variants on their own collections`,
 	}
 
 	rootCmd.AddCommand(
 		generate.NewGenerateCmd(),
 		run.NewRunCmd(),
+		scenario.NewScenarioCmd(),
 	)
 
 	if err := rootCmd.Execute(); err != nil {